@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/alee792/teamfit/internal/rest"
 	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/staticdata"
 	"github.com/alee792/teamfit/pkg/storage/jsonmap"
 	"github.com/alee792/teamfit/pkg/tft"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -13,26 +16,96 @@ import (
 
 func main() {
 	var (
-		restCfg rest.Config
-		tftCfg  tft.Config
+		restCfg  rest.Config
+		tftCfg   tft.Config
+		parallel int
+		cache    string
+		static   string
+		ratings  string
+		snaps    string
+		clusters string
+		achieve  string
+		awards   string
+		interval time.Duration
 
 		app = kingpin.New("tft", "Test CLI for TFT API")
 	)
 
 	app.Flag("addr", "Address to serve from").Default(":8080").StringVar(&restCfg.Addr)
 	app.Flag("key", "Riot API key").Envar("RIOT_API_KEY").Short('k').Required().StringVar(&tftCfg.APIKey)
+	app.Flag("platform", "default Riot platform, e.g. na1 or euw1").Envar("RIOT_PLATFORM").Default(string(tft.DefaultPlatform)).StringVar((*string)(&tftCfg.Platform))
+
+	app.Flag("parallelism", "concurrent Riot API calls").Default("4").IntVar(&parallel)
+	app.Flag("match-cache", "directory of cached JSON matches").Default("./matches").StringVar(&cache)
+	app.Flag("static", "directory of static data bundles").StringVar(&static)
+	app.Flag("ratings", "path to a JSON rating history").Default("./ratings.json").StringVar(&ratings)
+	app.Flag("snapshots", "path to JSON LP snapshots").Default("./snapshots.json").StringVar(&snaps)
+	app.Flag("clusters", "path to JSON composition clusters").Default("./clusters.json").StringVar(&clusters)
+	app.Flag("achievements", "path to a JSON achievements config, e.g. ./achievements.json").StringVar(&achieve)
+	app.Flag("awards", "path to JSON achievement awards").Default("./awards.json").StringVar(&awards)
+	app.Flag("snapshot-interval", "how often to snapshot every board's LP, 0 to disable").Default("1h").DurationVar(&interval)
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
+	tftCfg.Parallelism = parallel
+
+	platform, err := tft.ParsePlatform(string(tftCfg.Platform))
+	app.FatalIfError(err, "invalid platform")
+	tftCfg.Platform = platform
+
 	store, err := jsonmap.NewClient("./test.json")
 	if err != nil {
 		panic(err)
 	}
 
+	matches, err := jsonmap.NewMatchClient(cache)
+	if err != nil {
+		panic(err)
+	}
+
+	ratingStore, err := jsonmap.NewRatingClient(ratings)
+	if err != nil {
+		panic(err)
+	}
+
+	snapStore, err := jsonmap.NewSnapshotClient(snaps)
+	if err != nil {
+		panic(err)
+	}
+
+	clusterStore, err := jsonmap.NewClusterClient(clusters)
+	if err != nil {
+		panic(err)
+	}
+
+	awardStore, err := jsonmap.NewAwardClient(awards)
+	if err != nil {
+		panic(err)
+	}
+
+	var achievements *leaderboards.Achievements
+	if achieve != "" {
+		achievements, err = leaderboards.LoadAchievements(achieve)
+		app.FatalIfError(err, "invalid achievements")
+	}
+
+	var staticData *staticdata.Store
+	if static != "" {
+		staticData, err = staticdata.Load(static)
+		app.FatalIfError(err, "invalid static data")
+	}
+
 	// Create API Client and Leaderboard server.
 	b := &leaderboards.Server{
-		API:     tft.NewClient(http.DefaultClient, tftCfg),
-		Storage: store,
+		API:          leaderboards.NewCachedAPI(tft.NewClient(http.DefaultClient, tftCfg), matches),
+		Storage:      store,
+		Parallelism:  parallel,
+		Static:       staticData,
+		Ratings:      ratingStore,
+		Snapshots:    snapStore,
+		Clusters:     clusterStore,
+		Achievements: achievements,
+		Awards:       awardStore,
 	}
 
 	s, err := rest.NewServer(restCfg, rest.WithBoarder(b))
@@ -40,6 +113,12 @@ func main() {
 		panic(err)
 	}
 
+	if interval > 0 {
+		go b.RunSnapshots(context.Background(), interval, func(err error) {
+			s.Logger.Warnw("snapshot failed", "err", err)
+		})
+	}
+
 	if err := http.ListenAndServe(s.Config.Addr, s.Router); err != nil {
 		s.Logger.Fatal(err)
 	}
@@ -6,26 +6,53 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/staticdata"
+	"github.com/alee792/teamfit/pkg/storage/jsonmap"
 	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/alee792/teamfit/pkg/tft/tfttest"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func main() {
 	var (
-		app     = kingpin.New("tft", "Test CLI for TFT API")
-		key     = app.Flag("key", "Riot API key").Envar("RIOT_API_KEY").Short('k').Required().String()
-		verbose = app.Flag("verbose", "show units and traits").Short('v').Bool()
-		_       = app.HelpFlag.Short('h')
+		app      = kingpin.New("tft", "Test CLI for TFT API")
+		key      = app.Flag("key", "Riot API key, required unless replaying").Envar("RIOT_API_KEY").Short('k').String()
+		platform = app.Flag("platform", "Riot platform, e.g. na1 or euw1").Envar("RIOT_PLATFORM").Default(string(tft.DefaultPlatform)).Short('p').String()
+		verbose  = app.Flag("verbose", "show units and traits").Short('v').Bool()
+		parallel = app.Flag("parallelism", "concurrent Riot API calls").Default("4").Int()
+		cache    = app.Flag("cache", "directory of cached JSON matches").String()
+		record   = app.Flag("record", "record Riot API responses to a directory").String()
+		replay   = app.Flag("replay", "replay Riot API responses from a directory").String()
+		static   = app.Flag("static", "directory of static data bundles for unit, trait and item names").String()
+		_        = app.HelpFlag.Short('h')
 
 		results     = app.Command("results", "fetches recent match results").Default()
 		resultsArgs = setupCommonArgs(results)
 
 		stats     = app.Command("stats", "calculate stats from recent matches")
 		statsArgs = setupCommonArgs(stats)
+		statsSort = stats.Flag("sort", "metric to rank by, e.g. averageFinish, topFourRate, wins, damageDealt or lp").Default(string(leaderboards.DefaultMetric)).String()
+		statsThen = stats.Flag("then", "tie breaking metrics, in order").Strings()
+		statsMin  = stats.Flag("min-games", "games required to be ranked").Int()
+		statsFrom = stats.Flag("since", "start of the window, e.g. 2019-11-04, 7d, patch or season").String()
+		statsTo   = stats.Flag("until", "end of the window, e.g. 2019-11-11 or 1d").String()
 
-		// summoner = app.Arg("summoner", "Summoner Name").Required().String()
+		rivalries     = app.Command("rivalries", "show head-to-head records from shared matches")
+		rivalriesArgs = setupCommonArgs(rivalries)
+
+		comps     = app.Command("comps", "show the compositions players play and how they place")
+		compsArgs = setupCommonArgs(comps)
+
+		items     = app.Command("items", "show the items players build and how they place")
+		itemsArgs = setupCommonArgs(items)
+
+		summoner      = app.Command("summoner", "show a summoner's rank in each queue")
+		summonerNames = summoner.Arg("smnrs", "summoner names or Riot IDs").Strings()
 
 		ctx = context.Background()
 	)
@@ -33,12 +60,51 @@ func main() {
 	// Parse flags.
 	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
 
+	p, err := tft.ParsePlatform(*platform)
+	app.FatalIfError(err, "invalid platform")
+
+	// Optionally record or replay Riot API responses.
+	if *record != "" && *replay != "" {
+		app.Fatalf("--record and --replay are mutually exclusive")
+	}
+
+	if *key == "" && *replay == "" {
+		app.Fatalf("--key is required unless replaying")
+	}
+
+	httpClient := http.DefaultClient
+	switch {
+	case *replay != "":
+		httpClient = &http.Client{Transport: &tfttest.Replayer{Dir: *replay}}
+	case *record != "":
+		httpClient = &http.Client{Transport: &tfttest.Recorder{Dir: *record}}
+	}
+
 	// Create API Client and Leaderboard server.
+	var api leaderboards.API = tft.NewClient(httpClient, tft.Config{
+		APIKey:      *key,
+		Platform:    p,
+		Parallelism: *parallel,
+	})
+
+	if *cache != "" {
+		matches, err := jsonmap.NewMatchClient(*cache)
+		app.FatalIfError(err, "invalid match cache")
+
+		api = leaderboards.NewCachedAPI(api, matches)
+	}
+
+	var staticData *staticdata.Store
+	if *static != "" {
+		staticData, err = staticdata.Load(*static)
+		app.FatalIfError(err, "invalid static data")
+	}
+
 	boarder := leaderboards.Server{
-		API: tft.NewClient(http.DefaultClient, tft.Config{
-			APIKey: *key,
-		}),
-		Storage: nil,
+		API:         api,
+		Storage:     nil,
+		Parallelism: *parallel,
+		Static:      staticData,
 	}
 
 	// Setup writer to stdout.
@@ -46,14 +112,150 @@ func main() {
 	enc.SetIndent("", "  ")
 
 	switch cmd {
+	case items.FullCommand():
+		queues, err := itemsArgs.Queues()
+		app.FatalIfError(err, "invalid mode")
+
+		out, err := boarder.GetItems(ctx, itemsArgs.Names, &leaderboards.GetResultsArgs{
+			GameLimit: itemsArgs.Matches,
+			Queues:    queues,
+		})
+		if err != nil && !leaderboards.IsPartial(err) {
+			panic(err)
+		}
+
+		warnPartial(err)
+
+		// Sort players for stable output.
+		var names []string
+		for name := range out.Players {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Printf("%s\n", name)
+			printItems(out.Players[name])
+		}
+
+		if len(names) > 1 {
+			fmt.Printf("Everyone\n")
+			printItems(out.Board)
+		}
+	case comps.FullCommand():
+		queues, err := compsArgs.Queues()
+		app.FatalIfError(err, "invalid mode")
+
+		out, err := boarder.GetComps(ctx, compsArgs.Names, &leaderboards.GetResultsArgs{
+			GameLimit: compsArgs.Matches,
+			Queues:    queues,
+		})
+		if err != nil && !leaderboards.IsPartial(err) {
+			panic(err)
+		}
+
+		warnPartial(err)
+
+		// Sort players for stable output.
+		var names []string
+		for name := range out.Players {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Printf("%s\n", name)
+			printComps(out.Players[name])
+		}
+
+		if len(names) > 1 {
+			fmt.Printf("Everyone\n")
+			printComps(out.Board)
+		}
+	case rivalries.FullCommand():
+		queues, err := rivalriesArgs.Queues()
+		app.FatalIfError(err, "invalid mode")
+
+		out, err := boarder.GetResultsFromNames(ctx, rivalriesArgs.Names, &leaderboards.GetResultsArgs{
+			GameLimit: rivalriesArgs.Matches,
+			Queues:    queues,
+		})
+		if err != nil && !leaderboards.IsPartial(err) {
+			panic(err)
+		}
+
+		warnPartial(err)
+
+		rr := leaderboards.CalculateRivalries(out)
+		if len(rr) == 0 {
+			fmt.Printf("No shared matches\n")
+		}
+
+		// Sort players for stable output.
+		var names []string
+		for name := range rr {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Printf("%s\n", name)
+
+			var opponents []string
+			for opponent := range rr[name] {
+				opponents = append(opponents, opponent)
+			}
+
+			sort.Strings(opponents)
+
+			for _, opponent := range opponents {
+				h2h := rr[name][opponent]
+				fmt.Printf("  vs %-20s %dW %dL %dT  gap %+.2f  last %s\n", opponent, h2h.Wins, h2h.Losses, h2h.Ties, h2h.AveragePlacementGap, h2h.LastMeeting.Format("2006-01-02"))
+			}
+		}
+	case summoner.FullCommand():
+		for _, name := range *summonerNames {
+			smnr, err := boarder.GetSummoner(ctx, name)
+			if err != nil {
+				panic(err)
+			}
+
+			fmt.Printf("%s (level %d)\n", smnr.Name, smnr.SummonerLevel)
+
+			if len(smnr.Leagues) == 0 {
+				fmt.Printf("  unranked\n")
+			}
+
+			// Sort queues for stable output.
+			var queues []string
+			for queue := range smnr.Leagues {
+				queues = append(queues, queue)
+			}
+
+			sort.Strings(queues)
+
+			for _, queue := range queues {
+				le := smnr.Leagues[queue]
+				fmt.Printf("  %-22s %s %s %d LP (%dW %dL)\n", queue, le.Tier, le.Rank, le.LeaguePoints, le.Wins, le.Losses)
+			}
+		}
 	case results.FullCommand():
+		queues, err := resultsArgs.Queues()
+		app.FatalIfError(err, "invalid mode")
+
 		out, err := boarder.GetResultsFromNames(ctx, resultsArgs.Names, &leaderboards.GetResultsArgs{
 			GameLimit: resultsArgs.Matches,
+			Queues:    queues,
 		})
-		if err != nil {
+		if err != nil && !leaderboards.IsPartial(err) {
 			panic(err)
 		}
 
+		warnPartial(err)
+
 		for name, results := range out {
 			fmt.Printf("%s's Results\n", name)
 
@@ -75,13 +277,41 @@ func main() {
 			fmt.Printf("(Use -v to see units and traits)\n\n")
 		}
 	case stats.FullCommand():
-		out, err := boarder.GetStats(ctx, statsArgs.Names, &leaderboards.GetStatsArgs{
-			GameLimit: statsArgs.Matches,
+		metric, err := leaderboards.ParseMetric(*statsSort)
+		app.FatalIfError(err, "invalid sort")
+
+		window, err := leaderboards.ParseWindow(*statsFrom, *statsTo, time.Now())
+		app.FatalIfError(err, "invalid window")
+
+		queues, err := statsArgs.Queues()
+		app.FatalIfError(err, "invalid mode")
+
+		var tieBreakers []leaderboards.Metric
+		for _, raw := range *statsThen {
+			m, err := leaderboards.ParseMetric(raw)
+			app.FatalIfError(err, "invalid tie breaker")
+
+			tieBreakers = append(tieBreakers, m)
+		}
+
+		out, err := boarder.GetRanking(ctx, statsArgs.Names, &leaderboards.GetRankingArgs{
+			GetStatsArgs: leaderboards.GetStatsArgs{
+				GameLimit: statsArgs.Matches,
+				Window:    window,
+				Queues:    queues,
+			},
+			RankArgs: leaderboards.RankArgs{
+				Metric:      metric,
+				TieBreakers: tieBreakers,
+				MinGames:    *statsMin,
+			},
 		})
-		if err != nil {
+		if err != nil && !leaderboards.IsPartial(err) {
 			panic(err)
 		}
 
+		warnPartial(err)
+
 		if err := enc.Encode(&out); err != nil {
 			panic(err)
 		}
@@ -92,12 +322,46 @@ func main() {
 type CommonArgs struct {
 	Matches int
 	Names   []string
+	// Modes are modes or queue IDs, e.g. ranked or 1160.
+	Modes []string
+}
+
+// Queues parsed from Modes.
+func (args *CommonArgs) Queues() ([]int, error) {
+	return tft.ParseQueues(strings.Join(args.Modes, ","))
 }
 
 func setupCommonArgs(cmd *kingpin.CmdClause) *CommonArgs {
 	var args CommonArgs
 	cmd.Arg("smnrs", "summoner names").StringsVar(&args.Names)
-	cmd.Flag("matches", "number of matches to pull").Default("1").Short('m').IntVar(&args.Matches)
+	cmd.Flag("matches", "number of matches to pull, by default 1 or every match in a stats period").Default("0").Short('m').IntVar(&args.Matches)
+	cmd.Flag("mode", "only count a mode or queue ID, e.g. ranked, doubleup or 1100").StringsVar(&args.Modes)
 
 	return &args
 }
+
+func printComps(cc []leaderboards.CompStats) {
+	for _, c := range cc {
+		fmt.Printf("  %3d games  avg %.2f  top 4 %3.0f%%  %s\n", c.Games, c.AverageFinish, c.TopFourRate*100, c.Signature)
+	}
+}
+
+func printItems(b *leaderboards.ItemBreakdown) {
+	fmt.Printf("  %d completed, %d components\n", b.Completed, b.Components)
+
+	for _, i := range b.Items {
+		var carriers []string
+		for _, c := range i.Carriers {
+			carriers = append(carriers, fmt.Sprintf("%s (%d)", c.Unit, c.Builds))
+		}
+
+		fmt.Printf("  %3d builds  avg %.2f  top 4 %3.0f%%  %-24s %s\n", i.Builds, i.AverageFinish, i.TopFourRate*100, i.Item, strings.Join(carriers, ", "))
+	}
+}
+
+// warnPartial reports failures that did not prevent a command from completing.
+func warnPartial(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
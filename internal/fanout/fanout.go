@@ -0,0 +1,53 @@
+// Package fanout runs bounded, concurrent batches of work.
+package fanout
+
+import (
+	"context"
+	"sync"
+)
+
+// Do calls fn for every index in [0, n) with at most parallelism calls in flight.
+// Errors are returned at their call's index so callers can report partial failures,
+// and are all nil when every call succeeds. Calls not yet started when ctx is
+// done fail with ctx's error.
+func Do(ctx context.Context, n, parallelism int, fn func(ctx context.Context, i int) error) []error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		errs = make([]error, n)
+		sem  = make(chan struct{}, parallelism)
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		// Both may be ready at once, but calls never start once ctx is done.
+		if err := ctx.Err(); err != nil {
+			<-sem
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			errs[i] = fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return errs
+}
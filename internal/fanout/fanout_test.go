@@ -0,0 +1,103 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoBoundsParallelism(t *testing.T) {
+	tests := []struct {
+		parallelism int
+		want        int32
+	}{
+		{parallelism: 0, want: 1},
+		{parallelism: 1, want: 1},
+		{parallelism: 3, want: 3},
+		{parallelism: 50, want: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.parallelism), func(t *testing.T) {
+			var inFlight, max, calls int32
+
+			errs := Do(context.Background(), 20, tt.parallelism, func(ctx context.Context, i int) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+
+				for {
+					m := atomic.LoadInt32(&max)
+					if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+						break
+					}
+				}
+
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(5 * time.Millisecond)
+
+				return nil
+			})
+
+			if calls != 20 || len(errs) != 20 {
+				t.Fatalf("made %d calls with %d errors, want 20", calls, len(errs))
+			}
+
+			if max != tt.want {
+				t.Errorf("%d calls in flight, want %d", max, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoErrorsByIndex(t *testing.T) {
+	errs := Do(context.Background(), 10, 4, func(ctx context.Context, i int) error {
+		if i%3 == 0 {
+			return fmt.Errorf("call %d", i)
+		}
+
+		return nil
+	})
+
+	for i, err := range errs {
+		switch {
+		case i%3 == 0 && (err == nil || err.Error() != fmt.Sprintf("call %d", i)):
+			t.Errorf("errs[%d] = %v, want call %d", i, err, i)
+		case i%3 != 0 && err != nil:
+			t.Errorf("errs[%d] = %v, want nil", i, err)
+		}
+	}
+}
+
+func TestDoCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+
+	errs := Do(ctx, 5, 1, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 1 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	if calls != 2 {
+		t.Errorf("made %d calls, want 2", calls)
+	}
+
+	for i, err := range errs {
+		want := context.Canceled
+		if i < 2 {
+			want = nil
+		}
+
+		if !errors.Is(err, want) {
+			t.Errorf("errs[%d] = %v, want %v", i, err, want)
+		}
+	}
+}
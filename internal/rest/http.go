@@ -3,10 +3,15 @@ package rest
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -59,18 +64,65 @@ func (s *Server) GetStatsByNameHandler() http.HandlerFunc {
 			return
 		}
 
-		rawLimit := q.Get("matches")
-		matches, _ := strconv.Atoi(rawLimit)
-		if matches < 1 {
+		metric := leaderboards.DefaultMetric
+		if raw := q.Get("sort"); raw != "" {
+			m, err := leaderboards.ParseMetric(raw)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			metric = m
+		}
+
+		var tieBreakers []leaderboards.Metric
+		for _, raw := range q["then"] {
+			m, err := leaderboards.ParseMetric(raw)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			tieBreakers = append(tieBreakers, m)
+		}
+
+		minGames, _ := strconv.Atoi(q.Get("min_games"))
+
+		window, err := parseWindow(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// A Period is paged in full unless matches caps it.
+		matches, _ := strconv.Atoi(q.Get("matches"))
+		if matches < 1 && window.Period == "" {
 			matches = 10
 		}
 
+		queues, err := parseQueues(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// App logic.
-		ctx := context.Background()
-		out, err := s.Boarder.GetStats(ctx, names, &leaderboards.GetStatsArgs{
-			GameLimit: matches,
+		ctx := r.Context()
+		out, err := s.Boarder.GetRanking(ctx, names, &leaderboards.GetRankingArgs{
+			GetStatsArgs: leaderboards.GetStatsArgs{
+				GameLimit: matches,
+				Window:    window,
+				Queues:    queues,
+			},
+			RankArgs: leaderboards.RankArgs{
+				Metric:      metric,
+				TieBreakers: tieBreakers,
+				MinGames:    minGames,
+				QueueType:   q.Get("queue"),
+			},
 		})
-		if err != nil {
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
 			return
 		}
 
@@ -97,12 +149,20 @@ func (s *Server) GetResultsByNameHandler() http.HandlerFunc {
 			matches = 10
 		}
 
+		queues, err := parseQueues(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// App logic.
-		ctx := context.Background()
+		ctx := r.Context()
 		out, err := s.Boarder.GetResultsFromNames(ctx, names, &leaderboards.GetResultsArgs{
 			GameLimit: matches,
+			Queues:    queues,
 		})
-		if err != nil {
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
 			return
 		}
 
@@ -124,10 +184,16 @@ func (s *Server) GetSummonerHandler() http.HandlerFunc {
 		}
 
 		// App logic.
-		ctx := context.Background()
+		ctx := r.Context()
 		out, err := s.Boarder.GetSummoner(ctx, names[0])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.respondError(w, err)
+			return
+		}
+
+		out, err = s.Boarder.WithSummonerAwards(ctx, out)
+		if err != nil {
+			s.respondError(w, err)
 			return
 		}
 
@@ -139,8 +205,20 @@ func (s *Server) GetSummonerHandler() http.HandlerFunc {
 }
 
 type CreateLeaderBoardRequest struct {
-	Name      string
+	Name string
+	// Summoners are summoner names or gameName#tagLine Riot IDs.
 	Summoners []string
+	// League optionally seeds the board with an apex league's top players.
+	League *SeedLeague
+	// Queues the board counts by default, as modes or queue IDs,
+	// e.g. "ranked" or "1160". Empty counts every queue.
+	Queues []string
+}
+
+// SeedLeague selects the top players of an apex tier, e.g. CHALLENGER.
+type SeedLeague struct {
+	Tier  string
+	Limit int
 }
 
 func (s *Server) CreateLeaderboardHandler() http.HandlerFunc {
@@ -152,19 +230,32 @@ func (s *Server) CreateLeaderboardHandler() http.HandlerFunc {
 		defer r.Body.Close()
 
 		if err := json.NewDecoder(body).Decode(&in); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		board, err := s.populateBoard(ctx, in)
+		queues, err := tft.ParseQueues(strings.Join(in.Queues, ","))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if in.League != nil && !tft.IsApexTier(in.League.Tier) {
+			http.Error(w, "league tier must be master, grandmaster or challenger", http.StatusBadRequest)
+			return
+		}
+
+		board, err := s.populateBoard(ctx, in)
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
 			return
 		}
 
+		board.Queues = queues
+
 		out, err := s.Boarder.Storage.CreateLeaderboard(ctx, board)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.respondError(w, err)
 			return
 		}
 
@@ -189,7 +280,523 @@ func (s *Server) GetLeaderboardByNameHandler() http.HandlerFunc {
 
 		out, err := s.Boarder.Storage.GetLeaderboard(ctx, names[0])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.respondError(w, err)
+			return
+		}
+
+		out, err = s.Boarder.WithAwards(ctx, out)
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetLeagueHandler returns an apex tier's league, e.g. challenger.
+func (s *Server) GetLeagueHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tier := chi.URLParam(r, "tier")
+		if !tft.IsApexTier(tier) {
+			http.Error(w, "tier must be master, grandmaster or challenger", http.StatusBadRequest)
+			return
+		}
+
+		out, err := s.Boarder.API.GetApexLeague(r.Context(), tier)
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetRatedLadderHandler returns the top of a rated queue, e.g. RANKED_TFT_TURBO.
+func (s *Server) GetRatedLadderHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queue := chi.URLParam(r, "queue")
+
+		out, err := s.Boarder.API.GetRatedLadder(r.Context(), queue)
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetCacheStatsHandler reports match cache hits and misses, if the API is cached.
+func (s *Server) GetCacheStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cached, ok := s.Boarder.API.(interface {
+			CacheStats() leaderboards.CacheStats
+		})
+		if !ok {
+			http.Error(w, "match cache disabled", http.StatusNotFound)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, cached.CacheStats(), http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+func (s *Server) RefreshLeaderboardHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.RefreshLeaderboard(ctx, names[0])
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetRatingsHandler returns a Leaderboard's members' current ratings and
+// rating history without rating new matches.
+func (s *Server) GetRatingsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		out, err := s.Boarder.GetRatings(r.Context(), names[0])
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// UpdateRatingsHandler rates a Leaderboard's new shared matches
+// and returns its members' current ratings and rating history.
+func (s *Server) UpdateRatingsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		rawLimit := q.Get("matches")
+		matches, _ := strconv.Atoi(rawLimit)
+		if matches < 1 {
+			matches = 20
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.UpdateRatings(ctx, names[0], &leaderboards.GetResultsArgs{
+			GameLimit: matches,
+		})
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetRivalriesHandler returns head-to-head records between a Leaderboard's members.
+func (s *Server) GetRivalriesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		rawLimit := q.Get("matches")
+		matches, _ := strconv.Atoi(rawLimit)
+		if matches < 1 {
+			matches = 20
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetRivalries(ctx, names[0], &leaderboards.GetResultsArgs{
+			GameLimit: matches,
+		})
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// SnapshotLeaderboardHandler records a Leaderboard's current LP.
+func (s *Server) SnapshotLeaderboardHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.SnapshotLeaderboard(ctx, names[0])
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetLPDeltasHandler returns LP gained by a Leaderboard's members between
+// the since and until query parameters.
+func (s *Server) GetLPDeltasHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		since, until, err := parseTimeWindow(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetLPDeltas(ctx, names[0], queueType(q), since, until)
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetTimelineHandler returns a Summoner's LP snapshots between
+// the since and until query parameters.
+func (s *Server) GetTimelineHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		since, until, err := parseTimeWindow(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		smnr, err := s.Boarder.GetSummoner(ctx, names[0])
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		out, err := s.Boarder.GetTimeline(ctx, smnr.PUUID, queueType(q), since, until)
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// parseWindow parses the optional since and until query parameters,
+// e.g. since=7d or since=patch. See leaderboards.ParseWindow.
+func parseWindow(q url.Values) (leaderboards.Window, error) {
+	w, err := leaderboards.ParseWindow(q.Get("since"), q.Get("until"), time.Now())
+	if err != nil {
+		return w, errors.Wrap(err, "invalid window")
+	}
+
+	return w, nil
+}
+
+// parseTimeWindow is a parseWindow without a Period, for LP snapshots.
+func parseTimeWindow(q url.Values) (since, until time.Time, err error) {
+	w, err := parseWindow(q)
+	if err != nil {
+		return since, until, err
+	}
+
+	if w.Period != "" {
+		return since, until, errors.Errorf("since=%s is only supported by stats", w.Period)
+	}
+
+	return w.Since, w.Until, nil
+}
+
+// parseQueues parses the mode query parameters, e.g. mode=ranked&mode=1160.
+func parseQueues(q url.Values) ([]int, error) {
+	return tft.ParseQueues(strings.Join(q["mode"], ","))
+}
+
+// queueType query parameter, defaulting to ranked.
+func queueType(q url.Values) string {
+	if queue := q.Get("queue"); queue != "" {
+		return queue
+	}
+
+	return tft.QueueTypeRanked
+}
+
+// GetCompsByNameHandler returns the compositions Summoners play and their results.
+func (s *Server) GetCompsByNameHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		in, err := parseResultsArgs(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetComps(ctx, names, in)
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetBoardCompsHandler returns the compositions a Leaderboard's members play.
+func (s *Server) GetBoardCompsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		in, err := parseResultsArgs(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetBoardComps(ctx, names[0], in)
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetItemsByNameHandler returns the items Summoners build and their results.
+func (s *Server) GetItemsByNameHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		in, err := parseResultsArgs(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetItems(ctx, names, in)
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetBoardItemsHandler returns the items a Leaderboard's members build.
+func (s *Server) GetBoardItemsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		in, err := parseResultsArgs(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetBoardItems(ctx, names[0], in)
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// ClusterLeaderboardHandler clusters a Leaderboard's recent boards into archetypes per patch.
+func (s *Server) ClusterLeaderboardHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		in, err := parseResultsArgs(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		k, _ := strconv.Atoi(q.Get("k"))
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.ClusterLeaderboard(ctx, names[0], in, &leaderboards.ClusterArgs{
+			K: k,
+		})
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
+
+// GetMetaHandler returns a Leaderboard's archetype tier list for a patch,
+// defaulting to the newest clustered patch.
+func (s *Server) GetMetaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.GetMeta(ctx, names[0], q.Get("patch"))
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+
+		if out == nil {
+			http.Error(w, "patch has not been clustered", http.StatusNotFound)
 			return
 		}
 
@@ -200,7 +807,37 @@ func (s *Server) GetLeaderboardByNameHandler() http.HandlerFunc {
 	}
 }
 
+// parseResultsArgs parses the matches, since, until and mode query parameters.
+// Matches defaults to 20.
+func parseResultsArgs(q url.Values) (*leaderboards.GetResultsArgs, error) {
+	matches, _ := strconv.Atoi(q.Get("matches"))
+	if matches < 1 {
+		matches = 20
+	}
+
+	since, until, err := parseTimeWindow(q)
+	if err != nil {
+		return nil, err
+	}
+
+	queues, err := parseQueues(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaderboards.GetResultsArgs{
+		GameLimit: matches,
+		After:     since,
+		Before:    until,
+		Queues:    queues,
+	}, nil
+}
+
+// populateBoard looks up a new board's Summoners. Members of a seed League
+// that failed to resolve are reported by a *tft.BatchError.
 func (s *Server) populateBoard(ctx context.Context, in *CreateLeaderBoardRequest) (*leaderboards.Leaderboard, error) {
+	var partial error
+
 	board := &leaderboards.Leaderboard{
 		ID:   "",
 		Name: in.Name,
@@ -213,12 +850,30 @@ func (s *Server) populateBoard(ctx context.Context, in *CreateLeaderBoardRequest
 			return nil, errors.Wrapf(err, "failed to retrieve summoner: %s", name)
 		}
 
-		smnrs[smnr.Name] = *smnr
+		smnrs[smnr.PUUID] = *smnr
+	}
+
+	if in.League != nil {
+		league, err := s.Boarder.API.GetApexLeague(ctx, in.League.Tier)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve league: %s", in.League.Tier)
+		}
+
+		seeded, err := s.Boarder.LeaderboardFromLeague(ctx, in.Name, league, in.League.Limit)
+		if err != nil && !leaderboards.IsPartial(err) {
+			return nil, err
+		}
+
+		partial = err
+
+		for puuid, smnr := range seeded.Summoners {
+			smnrs[puuid] = smnr
+		}
 	}
 
 	board.Summoners = smnrs
 
-	return board, nil
+	return board, partial
 }
 
 func (s *Server) respondJSON(w http.ResponseWriter, v interface{}, status int) error {
@@ -234,3 +889,95 @@ func (s *Server) respondJSON(w http.ResponseWriter, v interface{}, status int) e
 
 	return nil
 }
+
+// headerPartialFailures reports the items of a partially successful request
+// that failed as a JSON object, e.g. {"Tactician One": "summoner-v1.getByName: 404 Not Found"}.
+const headerPartialFailures = "X-Partial-Failures"
+
+// partial reports the failures of a partially successful request in a header.
+// It must be called before the response is written.
+func (s *Server) partial(w http.ResponseWriter, err error) bool {
+	var batch *tft.BatchError
+	if !errors.As(err, &batch) {
+		return false
+	}
+
+	s.Logger.Warnw("partial results", "err", err)
+
+	failures := make(map[string]string, len(batch.Errors))
+	for item, err := range batch.Errors {
+		failures[item] = err.Error()
+	}
+
+	bb, jerr := json.Marshal(failures)
+	if jerr != nil {
+		s.Logger.Warnw("json encoding failed", "err", jerr)
+		return true
+	}
+
+	w.Header().Set(headerPartialFailures, string(bb))
+
+	return true
+}
+
+// respondError maps Riot API errors to the closest HTTP status.
+func (s *Server) respondError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, tft.ErrNotFound), errors.Is(err, leaderboards.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, tft.ErrRateLimited):
+		status = http.StatusTooManyRequests
+
+		var apiErr *tft.APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			secs := int(math.Ceil(apiErr.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+		}
+	case errors.Is(err, tft.ErrUnauthorized), errors.Is(err, tft.ErrForbidden):
+		// Our API key was rejected, not the caller's credentials.
+		status = http.StatusBadGateway
+	case errors.Is(err, tft.ErrServiceUnavailable):
+		status = http.StatusServiceUnavailable
+	}
+
+	if status == http.StatusInternalServerError {
+		s.Logger.Warnw("request failed", "err", err)
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+// UpdateAwardsHandler awards achievements earned in a Leaderboard's recent matches
+// and returns the new awards.
+func (s *Server) UpdateAwardsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		names, ok := q["name"]
+		if !ok || len(names[0]) < 1 {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		in, err := parseResultsArgs(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+
+		out, err := s.Boarder.UpdateAwards(ctx, names[0], in)
+		if err != nil && !s.partial(w, err) {
+			s.respondError(w, err)
+			return
+		}
+
+		// Respond.
+		if err := s.respondJSON(w, out, http.StatusOK); err != nil {
+			s.Logger.Warnw("json encoding failed", "err", err)
+		}
+	}
+}
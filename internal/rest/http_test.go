@@ -0,0 +1,232 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/alee792/teamfit/internal/rest"
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/storage/jsonmap"
+	"github.com/alee792/teamfit/pkg/tft/tfttest"
+)
+
+// newTestServer serves the REST API over fixtures, storing boards in a
+// temporary directory. Close it when finished.
+func newTestServer(t *testing.T) (*httptest.Server, *leaderboards.Server, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "rest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := tfttest.LoadFixtures("../../pkg/tft/tfttest/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := tfttest.NewServer(f)
+
+	store, err := jsonmap.NewClient(filepath.Join(dir, "boards.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	awards, err := jsonmap.NewAwardClient(filepath.Join(dir, "awards.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ratings, err := jsonmap.NewRatingClient(filepath.Join(dir, "ratings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	one := 1
+	b := &leaderboards.Server{
+		API:     api.APIClient(),
+		Storage: store,
+		Ratings: ratings,
+		Achievements: &leaderboards.Achievements{Rules: []leaderboards.Rule{
+			{ID: "win", Name: "Winner", Placement: &leaderboards.Range{Max: &one}},
+		}},
+		Awards: awards,
+	}
+
+	s, err := rest.NewServer(rest.Config{}, rest.WithBoarder(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(s.Router)
+
+	return srv, b, func() {
+		srv.Close()
+		api.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestMissingLeaderboard(t *testing.T) {
+	srv, _, done := newTestServer(t)
+	defer done()
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodGet, path: "/boards/missing/"},
+		{method: http.MethodPost, path: "/boards/missing/refresh"},
+		{method: http.MethodGet, path: "/boards/missing/ratings"},
+		{method: http.MethodGet, path: "/boards/missing/rivalries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+			}
+		})
+	}
+}
+
+// do a request, failing unless it responds OK, and decode its response into out.
+func do(t *testing.T, srv *httptest.Server, method, path string, body, out interface{}) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, srv.URL+path, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("%s %s: status = %d: %s", method, path, resp.StatusCode, msg)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLeaderboardAwards(t *testing.T) {
+	srv, _, done := newTestServer(t)
+	defer done()
+
+	do(t, srv, http.MethodPost, "/boards/", &rest.CreateLeaderBoardRequest{
+		Name:      "friends",
+		Summoners: []string{"Tactician One", "Tactician Two"},
+	}, nil)
+
+	var awarded []leaderboards.Award
+	do(t, srv, http.MethodPost, "/boards/friends/awards", nil, &awarded)
+
+	if len(awarded) != 1 || awarded[0].AchievementID != "win" {
+		t.Fatalf("awarded %+v, want one win", awarded)
+	}
+
+	// Awards are shown on the board they were earned on.
+	var board struct {
+		Summoners map[string]struct {
+			Name   string               `json:"name"`
+			Awards []leaderboards.Award `json:"awards"`
+		}
+	}
+
+	do(t, srv, http.MethodGet, "/boards/friends/", nil, &board)
+
+	for _, smnr := range board.Summoners {
+		want := 0
+		if smnr.Name == "Tactician Two" {
+			want = 1
+		}
+
+		if len(smnr.Awards) != want {
+			t.Errorf("%s has %d awards, want %d", smnr.Name, len(smnr.Awards), want)
+		}
+	}
+
+	if len(board.Summoners) != 2 {
+		t.Errorf("board has %d summoners, want 2", len(board.Summoners))
+	}
+}
+
+func TestUpdateRatings(t *testing.T) {
+	srv, _, done := newTestServer(t)
+	defer done()
+
+	do(t, srv, http.MethodPost, "/boards/", &rest.CreateLeaderBoardRequest{
+		Name:      "friends",
+		Summoners: []string{"Tactician One", "Tactician Two"},
+	}, nil)
+
+	// Reading ratings doesn't rate matches.
+	var before leaderboards.BoardRatings
+	do(t, srv, http.MethodGet, "/boards/friends/ratings", nil, &before)
+
+	if len(before.History) != 0 {
+		t.Fatalf("history before updating = %+v, want none", before.History)
+	}
+
+	// Concurrent updates rate the shared match once.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp, err := srv.Client().Post(srv.URL+"/boards/friends/ratings", "application/json", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var after leaderboards.BoardRatings
+	do(t, srv, http.MethodGet, "/boards/friends/ratings", nil, &after)
+
+	if len(after.History) != 2 {
+		t.Errorf("history = %+v, want a change for each player", after.History)
+	}
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"net/http"
 
+	"github.com/alee792/teamfit/pkg/tft"
 	"github.com/go-chi/cors"
 
 	"github.com/go-chi/chi"
@@ -19,11 +20,12 @@ func (s *Server) Route() {
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", headerPartialFailures},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	})
 	s.Router.Use(cors.Handler)
+	s.Router.Use(platformFromQuery("platform"))
 
 	s.Router.Route("/summoners", func(r chi.Router) {
 		r.Route("/{name}", func(r chi.Router) {
@@ -32,6 +34,9 @@ func (s *Server) Route() {
 			r.Get("/", s.GetSummonerHandler())
 			r.Get("/stats", s.GetStatsByNameHandler())
 			r.Get("/results", s.GetResultsByNameHandler())
+			r.Get("/timeline", s.GetTimelineHandler())
+			r.Get("/comps", s.GetCompsByNameHandler())
+			r.Get("/items", s.GetItemsByNameHandler())
 		})
 	})
 
@@ -43,10 +48,30 @@ func (s *Server) Route() {
 		r.Route("/{name}", func(r chi.Router) {
 			r.Use((pathToQuery("name", "name")))
 			r.Get("/", s.GetLeaderboardByNameHandler())
+			r.Post("/refresh", s.RefreshLeaderboardHandler())
+			r.Get("/ratings", s.GetRatingsHandler())
+			r.Post("/ratings", s.UpdateRatingsHandler())
+			r.Get("/rivalries", s.GetRivalriesHandler())
+			r.Post("/snapshots", s.SnapshotLeaderboardHandler())
+			r.Get("/lp", s.GetLPDeltasHandler())
+			r.Get("/comps", s.GetBoardCompsHandler())
+			r.Get("/items", s.GetBoardItemsHandler())
+			r.Post("/clusters", s.ClusterLeaderboardHandler())
+			r.Get("/meta", s.GetMetaHandler())
+			r.Post("/awards", s.UpdateAwardsHandler())
 		})
 
 		r.Post("/", s.CreateLeaderboardHandler())
 	})
+
+	s.Router.Route("/leagues", func(r chi.Router) {
+		r.Get("/rated/{queue}", s.GetRatedLadderHandler())
+		r.Get("/{tier}", s.GetLeagueHandler())
+	})
+
+	s.Router.Route("/debug", func(r chi.Router) {
+		r.Get("/cache", s.GetCacheStatsHandler())
+	})
 }
 
 func pathToQuery(pathParam, queryParam string) func(http.Handler) http.Handler {
@@ -62,3 +87,24 @@ func pathToQuery(pathParam, queryParam string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// platformFromQuery scopes a request's Riot API calls to an optional Platform.
+func platformFromQuery(queryParam string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.URL.Query().Get(queryParam)
+			if raw == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p, err := tft.ParsePlatform(raw)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(tft.WithPlatform(r.Context(), p)))
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package leaderboards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/staticdata"
+	"github.com/pkg/errors"
+)
+
+// Periods an achievement can be limited to with Rule.Once.
+const (
+	OnceDay   = "day"
+	OnceWeek  = "week"
+	OnceEver  = "ever"
+	onceMatch = ""
+)
+
+// Record stats a Rule can track.
+const (
+	RecordDamage            = "damageToPlayers"
+	RecordPlayersEliminated = "playersEliminated"
+	RecordGoldLeft          = "goldLeft"
+	RecordLastRound         = "lastRound"
+	RecordLevel             = "level"
+)
+
+// Achievements are Rules loaded from a config file, e.g.
+//
+//	{
+//	  "achievements": [
+//	    {"id": "eliminator", "name": "Eliminator", "playersEliminated": {"min": 3}},
+//	    {"id": "weekly-win", "name": "First Win of the Week", "placement": {"max": 1}, "once": "week"},
+//	    {"id": "three-star-four-cost", "name": "Big Spender", "unit": {"star": {"min": 3}, "cost": {"min": 4}}},
+//	    {"id": "top-damage", "name": "Highest Damage", "record": "damageToPlayers"}
+//	  ]
+//	}
+type Achievements struct {
+	Rules []Rule `json:"achievements"`
+}
+
+// Rule awards an achievement to results meeting all of its conditions.
+type Rule struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Placement         *Range     `json:"placement,omitempty"`
+	Level             *Range     `json:"level,omitempty"`
+	PlayersEliminated *Range     `json:"playersEliminated,omitempty"`
+	DamageToPlayers   *Range     `json:"damageToPlayers,omitempty"`
+	GoldLeft          *Range     `json:"goldLeft,omitempty"`
+	LastRound         *Range     `json:"lastRound,omitempty"`
+	Unit              *UnitRule  `json:"unit,omitempty"`
+	Trait             *TraitRule `json:"trait,omitempty"`
+
+	// Record awards results that beat every earlier result of a board's
+	// members for a stat, e.g. RecordDamage, whether or not those results
+	// earned the award. Only results newer than the last record are awarded.
+	Record string `json:"record,omitempty"`
+	// Once limits the award to once per player per period, i.e. OnceDay,
+	// OnceWeek or OnceEver. Periods are UTC. By default, every qualifying
+	// match earns the award.
+	Once string `json:"once,omitempty"`
+}
+
+// Range of values, inclusive. Nil bounds are unbounded.
+type Range struct {
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+}
+
+// UnitRule matches boards with at least Count units meeting its conditions.
+type UnitRule struct {
+	// CharacterID, e.g. TFT2_Ashe. Empty matches any unit.
+	CharacterID string `json:"characterId,omitempty"`
+	Star        *Range `json:"star,omitempty"`
+	// Cost uses static data if available, otherwise the unit's rarity.
+	Cost  *Range `json:"cost,omitempty"`
+	Items *Range `json:"items,omitempty"`
+	// Count defaults to 1.
+	Count int `json:"count,omitempty"`
+}
+
+// TraitRule matches boards with an active trait meeting its conditions.
+type TraitRule struct {
+	// Name, e.g. Set2_Ranger. Empty matches any trait.
+	Name        string `json:"name,omitempty"`
+	TierCurrent *Range `json:"tierCurrent,omitempty"`
+	// Style is the trait's badge, 4 being chromatic.
+	Style *Range `json:"style,omitempty"`
+	// Count defaults to 1.
+	Count int `json:"count,omitempty"`
+}
+
+// Award of an achievement to a board member for a match.
+type Award struct {
+	BoardID       string    `json:"boardId"`
+	AchievementID string    `json:"achievementId"`
+	Name          string    `json:"name"`
+	PUUID         string    `json:"-"`
+	MatchID       string    `json:"matchId"`
+	EarnedAt      time.Time `json:"earnedAt"`
+	// Value of a Record's stat.
+	Value int `json:"value,omitempty"`
+}
+
+// AwardStore persists Awards.
+type AwardStore interface {
+	// GetAwards of a board's members, oldest first.
+	GetAwards(ctx context.Context, boardID string) ([]Award, error)
+	// ListAwards of a player across every board, oldest first.
+	ListAwards(ctx context.Context, puuid string) ([]Award, error)
+	PutAwards(ctx context.Context, boardID string, awards []Award) error
+}
+
+// LoadAchievements from a JSON config file.
+func LoadAchievements(path string) (*Achievements, error) {
+	bb, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var a Achievements
+	if err := json.Unmarshal(bb, &a); err != nil {
+		return nil, errors.Wrapf(err, "invalid achievements %s", path)
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid achievements %s", path)
+	}
+
+	return &a, nil
+}
+
+// Validate Rules have unique IDs and known periods and records.
+func (a *Achievements) Validate() error {
+	ids := make(map[string]bool)
+	for _, r := range a.Rules {
+		if r.ID == "" {
+			return errors.Errorf("achievement %q is missing an id", r.Name)
+		}
+
+		if ids[r.ID] {
+			return errors.Errorf("duplicate achievement %s", r.ID)
+		}
+
+		ids[r.ID] = true
+
+		switch r.Once {
+		case onceMatch, OnceDay, OnceWeek, OnceEver:
+		default:
+			return errors.Errorf("achievement %s: unknown period %q", r.ID, r.Once)
+		}
+
+		if _, ok := recordValues[r.Record]; r.Record != "" && !ok {
+			return errors.Errorf("achievement %s: unknown record %q", r.ID, r.Record)
+		}
+	}
+
+	return nil
+}
+
+// recordValues of a Result.
+var recordValues = map[string]func(r *Result) int{
+	RecordDamage:            func(r *Result) int { return r.TotalDamageToPlayers },
+	RecordPlayersEliminated: func(r *Result) int { return r.PlayersEliminated },
+	RecordGoldLeft:          func(r *Result) int { return r.GoldLeft },
+	RecordLastRound:         func(r *Result) int { return r.LastRound },
+	RecordLevel:             func(r *Result) int { return r.Level },
+}
+
+// Evaluate a board's results, oldest first, returning new Awards.
+// Matches that already earned an achievement are not awarded again.
+// Records start from the existing Awards and are raised by every result.
+func (a *Achievements) Evaluate(boardID string, existing []Award, results PUUIDResults) []Award {
+	var (
+		earned   = make(map[string]bool)      // Key = achievement, player and match or period
+		records  = make(map[string]int)       // Key = Rule.ID
+		recorded = make(map[string]time.Time) // Key = Rule.ID
+		awards   []Award
+	)
+
+	for _, aw := range existing {
+		earned[awardKey(aw.AchievementID, aw.PUUID, aw.MatchID)] = true
+		for _, r := range a.Rules {
+			if r.ID == aw.AchievementID && r.Once != onceMatch {
+				earned[awardKey(r.ID, aw.PUUID, period(r.Once, aw.EarnedAt))] = true
+			}
+		}
+
+		if aw.Value > records[aw.AchievementID] {
+			records[aw.AchievementID] = aw.Value
+		}
+
+		if aw.EarnedAt.After(recorded[aw.AchievementID]) {
+			recorded[aw.AchievementID] = aw.EarnedAt
+		}
+	}
+
+	var all []Result
+	for _, rr := range results {
+		all = append(all, rr...)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if !all[i].StartedAt.Equal(all[j].StartedAt) {
+			return all[i].StartedAt.Before(all[j].StartedAt)
+		}
+
+		return all[i].PUUID < all[j].PUUID
+	})
+
+	for i := range all {
+		res := &all[i]
+		for _, rule := range a.Rules {
+			var (
+				value  int
+				record bool
+			)
+
+			// Every result counts towards a record, but only results after
+			// the last awarded record can beat it.
+			if rule.Record != "" {
+				value = recordValues[rule.Record](res)
+				record = value > records[rule.ID] && res.StartedAt.After(recorded[rule.ID])
+				records[rule.ID] = maxInt(records[rule.ID], value)
+			}
+
+			if earned[awardKey(rule.ID, res.PUUID, res.MatchID)] || !rule.matches(res) {
+				continue
+			}
+
+			if rule.Record != "" && !record {
+				continue
+			}
+
+			if rule.Once != onceMatch {
+				key := awardKey(rule.ID, res.PUUID, period(rule.Once, res.StartedAt))
+				if earned[key] {
+					continue
+				}
+
+				earned[key] = true
+			}
+
+			earned[awardKey(rule.ID, res.PUUID, res.MatchID)] = true
+			awards = append(awards, Award{
+				BoardID:       boardID,
+				AchievementID: rule.ID,
+				Name:          rule.Name,
+				PUUID:         res.PUUID,
+				MatchID:       res.MatchID,
+				EarnedAt:      res.StartedAt,
+				Value:         value,
+			})
+		}
+	}
+
+	return awards
+}
+
+func awardKey(id, puuid, scope string) string {
+	return id + "/" + puuid + "/" + scope
+}
+
+// period a time falls in.
+func period(once string, t time.Time) string {
+	t = t.UTC()
+	switch once {
+	case OnceDay:
+		return t.Format("2006-01-02")
+	case OnceWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return "ever"
+	}
+}
+
+// matches reports whether a result meets all of a Rule's conditions.
+func (rule *Rule) matches(r *Result) bool {
+	return rule.Placement.contains(r.Placement) &&
+		rule.Level.contains(r.Level) &&
+		rule.PlayersEliminated.contains(r.PlayersEliminated) &&
+		rule.DamageToPlayers.contains(r.TotalDamageToPlayers) &&
+		rule.GoldLeft.contains(r.GoldLeft) &&
+		rule.LastRound.contains(r.LastRound) &&
+		rule.Unit.matches(r) &&
+		rule.Trait.matches(r)
+}
+
+func (rng *Range) contains(v int) bool {
+	if rng == nil {
+		return true
+	}
+
+	return (rng.Min == nil || v >= *rng.Min) && (rng.Max == nil || v <= *rng.Max)
+}
+
+func (ur *UnitRule) matches(r *Result) bool {
+	if ur == nil {
+		return true
+	}
+
+	var n int
+	for _, u := range r.Units {
+		if ur.CharacterID != "" && ur.CharacterID != u.CharacterID {
+			continue
+		}
+
+		cost := u.Cost
+		if cost == 0 {
+			cost = staticdata.RarityCost(r.Set, u.Rarity)
+		}
+
+		if ur.Star.contains(u.Tier) && ur.Cost.contains(cost) && ur.Items.contains(u.ItemCount()) {
+			n++
+		}
+	}
+
+	return n >= maxInt(ur.Count, 1)
+}
+
+func (tr *TraitRule) matches(r *Result) bool {
+	if tr == nil {
+		return true
+	}
+
+	var n int
+	for _, t := range r.Traits {
+		if t.TierCurrent == 0 || (tr.Name != "" && tr.Name != t.Name) {
+			continue
+		}
+
+		if tr.TierCurrent.contains(t.TierCurrent) && tr.Style.contains(t.Style) {
+			n++
+		}
+	}
+
+	return n >= maxInt(tr.Count, 1)
+}
+
+// UpdateAwards evaluates a Leaderboard's recent results and stores new Awards.
+// Failures to retrieve some results are reported by a *tft.BatchError;
+// awards are still given for those that were retrieved.
+func (s *Server) UpdateAwards(ctx context.Context, id string, in *GetResultsArgs) ([]Award, error) {
+	if s.Achievements == nil || s.Awards == nil {
+		return nil, errors.New("achievements are not configured")
+	}
+
+	existing, err := s.Awards.GetAwards(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get awards failed")
+	}
+
+	out, rerr := s.GetResultsFromLeaderboard(ctx, id, in)
+	if rerr != nil && !IsPartial(rerr) {
+		return nil, rerr
+	}
+
+	results := make(PUUIDResults)
+	for _, rr := range out {
+		for _, r := range rr {
+			results[r.PUUID] = append(results[r.PUUID], r)
+		}
+	}
+
+	awards := s.Achievements.Evaluate(id, existing, results)
+	if len(awards) > 0 {
+		if err := s.Awards.PutAwards(ctx, id, awards); err != nil {
+			return nil, errors.Wrap(err, "put awards failed")
+		}
+	}
+
+	return awards, rerr
+}
+
+// WithAwards attaches each member's Awards to a Leaderboard.
+// Boards are returned unchanged if awards are not configured.
+func (s *Server) WithAwards(ctx context.Context, board *Leaderboard) (*Leaderboard, error) {
+	if s.Awards == nil || board == nil {
+		return board, nil
+	}
+
+	awards, err := s.Awards.GetAwards(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get awards failed")
+	}
+
+	byPUUID := make(map[string][]Award)
+	for _, aw := range awards {
+		byPUUID[aw.PUUID] = append(byPUUID[aw.PUUID], aw)
+	}
+
+	out := *board
+	out.Summoners = make(map[string]Summoner)
+	for puuid, smnr := range board.Summoners {
+		smnr.Awards = byPUUID[puuid]
+		out.Summoners[puuid] = smnr
+	}
+
+	return &out, nil
+}
+
+// WithSummonerAwards attaches a Summoner's Awards from every board.
+func (s *Server) WithSummonerAwards(ctx context.Context, smnr *Summoner) (*Summoner, error) {
+	if s.Awards == nil || smnr == nil {
+		return smnr, nil
+	}
+
+	awards, err := s.Awards.ListAwards(ctx, smnr.PUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list awards failed")
+	}
+
+	smnr.Awards = awards
+
+	return smnr, nil
+}
@@ -0,0 +1,101 @@
+package leaderboards_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+func TestEvaluate(t *testing.T) {
+	var (
+		day   = time.Date(2019, 11, 4, 12, 0, 0, 0, time.UTC)
+		one   = 1
+		three = 3
+	)
+
+	result := func(id string, at time.Time, placement, damage int, units ...tft.Unit) leaderboards.Result {
+		return leaderboards.Result{
+			MatchID:   id,
+			StartedAt: at,
+			Participant: tft.Participant{
+				PUUID:                puuidOne,
+				Placement:            placement,
+				TotalDamageToPlayers: damage,
+				Units:                units,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		rule     leaderboards.Rule
+		existing []leaderboards.Award
+		results  []leaderboards.Result
+		want     []string // Match IDs awarded, oldest first
+	}{
+		{
+			name:    "records rise",
+			rule:    leaderboards.Rule{ID: "top-damage", Record: leaderboards.RecordDamage},
+			results: []leaderboards.Result{result("a", day, 1, 50), result("b", day.Add(time.Hour), 1, 40), result("c", day.Add(2*time.Hour), 1, 60)},
+			want:    []string{"a", "c"},
+		},
+		{
+			name:    "unqualified results set records",
+			rule:    leaderboards.Rule{ID: "top-damage", Record: leaderboards.RecordDamage, Placement: &leaderboards.Range{Max: &one}},
+			results: []leaderboards.Result{result("a", day, 5, 100), result("b", day.Add(time.Hour), 1, 80)},
+		},
+		{
+			name:    "once limited results set records",
+			rule:    leaderboards.Rule{ID: "top-damage", Record: leaderboards.RecordDamage, Once: leaderboards.OnceDay},
+			results: []leaderboards.Result{result("a", day, 1, 50), result("b", day.Add(time.Hour), 1, 100), result("c", day.Add(24*time.Hour), 1, 70)},
+			want:    []string{"a"},
+		},
+		{
+			name:     "stored records",
+			rule:     leaderboards.Rule{ID: "top-damage", Record: leaderboards.RecordDamage},
+			existing: []leaderboards.Award{{AchievementID: "top-damage", PUUID: puuidOne, MatchID: "a", EarnedAt: day, Value: 90}},
+			results:  []leaderboards.Result{result("b", day.Add(time.Hour), 1, 80), result("c", day.Add(2*time.Hour), 1, 95)},
+			want:     []string{"c"},
+		},
+		{
+			name:     "results before stored records",
+			rule:     leaderboards.Rule{ID: "top-damage", Record: leaderboards.RecordDamage},
+			existing: []leaderboards.Award{{AchievementID: "top-damage", PUUID: puuidOne, MatchID: "b", EarnedAt: day, Value: 90}},
+			results:  []leaderboards.Result{result("a", day.Add(-time.Hour), 1, 120), result("b", day, 1, 90)},
+		},
+		{
+			name: "items named and numbered once",
+			rule: leaderboards.Rule{ID: "loaded", Unit: &leaderboards.UnitRule{Items: &leaderboards.Range{Min: &three}}},
+			results: []leaderboards.Result{
+				result("a", day, 1, 0, tft.Unit{Items: []int{1, 2}, ItemNames: []string{"TFT_Item_BFSword", "TFT_Item_ChainVest"}}),
+				result("b", day.Add(time.Hour), 1, 0, tft.Unit{Items: []int{1, 2, 3}}),
+			},
+			want: []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &leaderboards.Achievements{Rules: []leaderboards.Rule{tt.rule}}
+			awards := a.Evaluate("board", tt.existing, leaderboards.PUUIDResults{puuidOne: tt.results})
+
+			var got []string
+			for _, aw := range awards {
+				got = append(got, aw.MatchID)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("awarded %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("awarded %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package leaderboards
+
+import (
+	"context"
+	"sort"
+
+	"github.com/alee792/teamfit/internal/fanout"
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// RefreshLeaderboard updates each member's name and leagues by PUUID,
+// so renamed Summoners stay on their boards. Members that could not be
+// refreshed keep their previous details and are reported by a *tft.BatchError.
+func (s *Server) RefreshLeaderboard(ctx context.Context, id string) (*Leaderboard, error) {
+	board, err := s.Storage.GetLeaderboard(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get leaderboard failed")
+	}
+
+	var members []Summoner
+	for _, smnr := range board.Summoners {
+		members = append(members, smnr)
+	}
+
+	refreshed := make([]*Summoner, len(members))
+	errs := fanout.Do(ctx, len(members), s.parallelism(), func(ctx context.Context, i int) error {
+		if members[i].Platform != "" {
+			ctx = tft.WithPlatform(ctx, members[i].Platform)
+		}
+
+		var err error
+		refreshed[i], err = s.GetSummonerByPUUID(ctx, members[i].PUUID)
+
+		return err
+	})
+
+	failures := make(map[string]error)
+	smnrs := make(map[string]Summoner)
+	for i, smnr := range members {
+		if errs[i] != nil {
+			failures[smnr.Name] = errs[i]
+			smnrs[smnr.PUUID] = smnr
+			continue
+		}
+
+		// Keep the Platform the member was added from.
+		refreshed[i].Platform = smnr.Platform
+		smnrs[smnr.PUUID] = *refreshed[i]
+	}
+
+	// Storage may share the board with concurrent readers, so it's copied.
+	updated := *board
+	updated.Summoners = smnrs
+
+	out, err := s.Storage.UpdateLeaderboard(ctx, id, &updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "update leaderboard failed")
+	}
+
+	return out, batchError(failures)
+}
+
+// LeaderboardFromLeague builds an unsaved Leaderboard of a league's top players by LP.
+// A limit < 1 includes the entire league. Players that could not be retrieved
+// are reported by a *tft.BatchError.
+func (s *Server) LeaderboardFromLeague(ctx context.Context, name string, league *tft.LeagueList, limit int) (*Leaderboard, error) {
+	entries := make([]tft.LeagueItem, len(league.Entries))
+	copy(entries, league.Entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LeaguePoints > entries[j].LeaguePoints
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	members := make([]*Summoner, len(entries))
+	errs := fanout.Do(ctx, len(entries), s.parallelism(), func(ctx context.Context, i int) error {
+		smnr, err := s.API.GetSummonerByID(ctx, entries[i].SummonerID)
+		if err != nil {
+			return err
+		}
+
+		members[i], err = s.withLeagues(ctx, smnr)
+
+		return err
+	})
+
+	var (
+		failures = make(map[string]error)
+		smnrs    = make(map[string]Summoner)
+	)
+
+	for i, err := range errs {
+		if err != nil {
+			failures[entries[i].SummonerName] = err
+			continue
+		}
+
+		smnrs[members[i].PUUID] = *members[i]
+	}
+
+	return &Leaderboard{
+		Name:      name,
+		Summoners: smnrs,
+	}, batchError(failures)
+}
@@ -0,0 +1,69 @@
+package leaderboards
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// CachedAPI serves matches from a MatchStore and only calls
+// the underlying API for matches it has not seen.
+type CachedAPI struct {
+	// Counters are accessed atomically and kept first for alignment.
+	hits   int64
+	misses int64
+
+	API
+	Store MatchStore
+}
+
+// NewCachedAPI decorates an API with a MatchStore.
+func NewCachedAPI(api API, store MatchStore) *CachedAPI {
+	return &CachedAPI{
+		API:   api,
+		Store: store,
+	}
+}
+
+// CacheStats count lookups made by a CachedAPI.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// CacheStats since the CachedAPI was created.
+func (c *CachedAPI) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+	}
+}
+
+// GetMatch from the Store, falling back to the API.
+// Matches are immutable, so a stored match never needs to be refreshed.
+func (c *CachedAPI) GetMatch(ctx context.Context, in *tft.GetMatchRequest) (*tft.GetMatchResponse, error) {
+	m, err := c.Store.GetMatch(ctx, in.MatchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read cached match %s", in.MatchID)
+	}
+
+	if m != nil {
+		atomic.AddInt64(&c.hits, 1)
+		return &tft.GetMatchResponse{Match: *m}, nil
+	}
+
+	atomic.AddInt64(&c.misses, 1)
+
+	out, err := c.API.GetMatch(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Store.PutMatch(ctx, in.MatchID, &out.Match); err != nil {
+		return nil, errors.Wrapf(err, "failed to cache match %s", in.MatchID)
+	}
+
+	return out, nil
+}
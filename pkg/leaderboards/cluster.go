@@ -0,0 +1,424 @@
+package leaderboards
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// Clustering defaults.
+const (
+	// MaxClusters per patch when ClusterArgs.K is unset.
+	MaxClusters = 8
+	// clusterIterations bounds k-means if assignments never settle.
+	clusterIterations = 50
+)
+
+// Archetype is a cluster of similar final boards on a patch.
+type Archetype struct {
+	ID int `json:"id"`
+	// Name of the cluster's dominant traits, e.g. "Glacial + Berserker".
+	Name string `json:"name"`
+	// Tier from S to D by AverageFinish.
+	Tier string `json:"tier"`
+	// Traits and Units most common in the cluster, most common first.
+	Traits        []string `json:"traits"`
+	Units         []string `json:"units"`
+	Games         int      `json:"games"`
+	AverageFinish float32  `json:"averageFinish"`
+	TopFourRate   float32  `json:"topFourRate"`
+}
+
+// ClusterAssignment of a player's board in a match.
+type ClusterAssignment struct {
+	MatchID   string `json:"matchId"`
+	PUUID     string `json:"puuid"`
+	ClusterID int    `json:"clusterId"`
+}
+
+// PatchClusters are a patch's Archetypes, best first, and the boards in them.
+type PatchClusters struct {
+	Patch       string              `json:"patch"`
+	Archetypes  []Archetype         `json:"archetypes"`
+	Assignments []ClusterAssignment `json:"assignments"`
+}
+
+// ClusterStore persists a Leaderboard's PatchClusters.
+type ClusterStore interface {
+	// PutClusters replaces a board's clusters for each of their patches.
+	PutClusters(ctx context.Context, boardID string, clusters []PatchClusters) error
+	// ListClusters of a board, newest patch first.
+	ListClusters(ctx context.Context, boardID string) ([]PatchClusters, error)
+}
+
+// ClusterArgs configure clustering.
+type ClusterArgs struct {
+	// K clusters per patch. Defaults to √(games / 2), up to MaxClusters.
+	K int
+}
+
+// tiers by maximum AverageFinish.
+var tiers = []struct {
+	name      string
+	maxFinish float32
+}{
+	{"S", 3.5},
+	{"A", 4},
+	{"B", 4.5},
+	{"C", 5},
+	{"D", 8},
+}
+
+// vector of a board's features, i.e. active traits weighted by tier
+// and units weighted by star level, normalized to unit length.
+type vector map[string]float64
+
+func vectorOf(r Result) vector {
+	v := make(vector)
+	for _, t := range r.Traits {
+		if t.TierCurrent > 0 {
+			v["trait:"+traitName(t)] += float64(t.TierCurrent)
+		}
+	}
+
+	for _, u := range r.Units {
+		star := u.Tier
+		if star < 1 {
+			star = 1
+		}
+
+		v["unit:"+unitName(u)] += float64(star)
+	}
+
+	var norm float64
+	for _, w := range v {
+		norm += w * w
+	}
+
+	norm = math.Sqrt(norm)
+	for k := range v {
+		v[k] /= norm
+	}
+
+	return v
+}
+
+// distance between two vectors.
+func distance(a, b vector) float64 {
+	var sum float64
+	for k, x := range a {
+		d := x - b[k]
+		sum += d * d
+	}
+
+	for k, y := range b {
+		if _, ok := a[k]; !ok {
+			sum += y * y
+		}
+	}
+
+	return sum
+}
+
+// Cluster results into Archetypes per patch with k-means. Clustering is
+// deterministic: results are ordered by match and player, and centroids
+// are seeded with the farthest points from those already chosen.
+// in may be nil.
+func Cluster(results PUUIDResults, in *ClusterArgs) []PatchClusters {
+	if in == nil {
+		in = &ClusterArgs{}
+	}
+
+	byPatch := make(map[string][]Result)
+	for _, rr := range results {
+		for _, r := range rr {
+			patch := tft.ParsePatch(r.GameVersion)
+			byPatch[patch] = append(byPatch[patch], r)
+		}
+	}
+
+	var out []PatchClusters
+	for patch, rr := range byPatch {
+		sort.Slice(rr, func(i, j int) bool {
+			if rr[i].MatchID != rr[j].MatchID {
+				return rr[i].MatchID < rr[j].MatchID
+			}
+
+			return rr[i].PUUID < rr[j].PUUID
+		})
+
+		out = append(out, clusterPatch(patch, rr, in.k(len(rr))))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return tft.ComparePatches(out[i].Patch, out[j].Patch) > 0
+	})
+
+	return out
+}
+
+func (in *ClusterArgs) k(n int) int {
+	k := in.K
+	if k < 1 {
+		k = int(math.Sqrt(float64(n) / 2))
+		if k > MaxClusters {
+			k = MaxClusters
+		}
+	}
+
+	if k < 1 {
+		k = 1
+	}
+
+	if k > n {
+		k = n
+	}
+
+	return k
+}
+
+// clusterPatch with k-means.
+func clusterPatch(patch string, rr []Result, k int) PatchClusters {
+	vectors := make([]vector, len(rr))
+	for i, r := range rr {
+		vectors[i] = vectorOf(r)
+	}
+
+	// Seed centroids with the farthest points.
+	centroids := []vector{vectors[0]}
+	for len(centroids) < k {
+		best, bestDist := -1, -1.0
+		for i, v := range vectors {
+			d := math.Inf(1)
+			for _, c := range centroids {
+				d = math.Min(d, distance(v, c))
+			}
+
+			if d > bestDist {
+				best, bestDist = i, d
+			}
+		}
+
+		centroids = append(centroids, vectors[best])
+	}
+
+	assigned := make([]int, len(rr))
+	for iter := 0; iter < clusterIterations; iter++ {
+		changed := iter == 0
+		for i, v := range vectors {
+			nearest, nearestDist := 0, math.Inf(1)
+			for c, centroid := range centroids {
+				if d := distance(v, centroid); d < nearestDist {
+					nearest, nearestDist = c, d
+				}
+			}
+
+			if assigned[i] != nearest {
+				assigned[i] = nearest
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+
+		// Move centroids to the mean of their members.
+		sums := make([]vector, k)
+		counts := make([]int, k)
+		for i, v := range vectors {
+			c := assigned[i]
+			if sums[c] == nil {
+				sums[c] = make(vector)
+			}
+
+			for f, w := range v {
+				sums[c][f] += w
+			}
+
+			counts[c]++
+		}
+
+		for c := range centroids {
+			// Empty clusters keep their centroid.
+			if counts[c] == 0 {
+				continue
+			}
+
+			for f := range sums[c] {
+				sums[c][f] /= float64(counts[c])
+			}
+
+			centroids[c] = sums[c]
+		}
+	}
+
+	return describeClusters(patch, rr, centroids, assigned)
+}
+
+// describeClusters names, rates and tiers clusters. Empty clusters are dropped.
+func describeClusters(patch string, rr []Result, centroids []vector, assigned []int) PatchClusters {
+	members := make([][]Result, len(centroids))
+	for i, c := range assigned {
+		members[c] = append(members[c], rr[i])
+	}
+
+	pc := PatchClusters{Patch: patch}
+	ids := make(map[int]int) // Key = cluster index, Value = Archetype.ID
+	for c, mm := range members {
+		if len(mm) == 0 {
+			continue
+		}
+
+		stat := CalculateStats(mm, nil)
+		a := Archetype{
+			Traits:        topFeatures(centroids[c], "trait:", 3),
+			Units:         topFeatures(centroids[c], "unit:", 4),
+			Games:         stat.Games,
+			AverageFinish: stat.AverageFinish,
+			TopFourRate:   stat.TopFourRate,
+		}
+
+		a.Name = strings.Join(topFeatures(centroids[c], "trait:", 2), " + ")
+		if a.Name == "" {
+			a.Name = strings.Join(topFeatures(centroids[c], "unit:", 2), " + ")
+		}
+
+		for _, t := range tiers {
+			if a.AverageFinish <= t.maxFinish {
+				a.Tier = t.name
+				break
+			}
+		}
+
+		pc.Archetypes = append(pc.Archetypes, a)
+		ids[c] = len(pc.Archetypes) - 1
+	}
+
+	// Best archetypes first, renumbering IDs to match.
+	order := make([]int, len(pc.Archetypes))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		ai, aj := pc.Archetypes[order[i]], pc.Archetypes[order[j]]
+		if ai.AverageFinish != aj.AverageFinish {
+			return ai.AverageFinish < aj.AverageFinish
+		}
+
+		return ai.Games > aj.Games
+	})
+
+	renumbered := make([]int, len(order))
+	archetypes := make([]Archetype, len(order))
+	names := make(map[string]int)
+	for id, i := range order {
+		a := pc.Archetypes[i]
+		a.ID = id + 1
+
+		// Distinguish clusters that share dominant traits.
+		names[a.Name]++
+		if n := names[a.Name]; n > 1 {
+			a.Name = fmt.Sprintf("%s (%d)", a.Name, n)
+		}
+
+		archetypes[id] = a
+		renumbered[i] = a.ID
+	}
+
+	pc.Archetypes = archetypes
+
+	for i, r := range rr {
+		pc.Assignments = append(pc.Assignments, ClusterAssignment{
+			MatchID:   r.MatchID,
+			PUUID:     r.PUUID,
+			ClusterID: renumbered[ids[assigned[i]]],
+		})
+	}
+
+	return pc
+}
+
+// topFeatures of a centroid with a prefix, heaviest first.
+func topFeatures(v vector, prefix string, n int) []string {
+	var features []string
+	for f := range v {
+		if strings.HasPrefix(f, prefix) {
+			features = append(features, f)
+		}
+	}
+
+	sort.Slice(features, func(i, j int) bool {
+		if v[features[i]] != v[features[j]] {
+			return v[features[i]] > v[features[j]]
+		}
+
+		return features[i] < features[j]
+	})
+
+	if len(features) > n {
+		features = features[:n]
+	}
+
+	for i, f := range features {
+		features[i] = strings.TrimPrefix(f, prefix)
+	}
+
+	return features
+}
+
+// ClusterLeaderboard clusters a Leaderboard's recent boards per patch and
+// stores the clusters, replacing those previously stored for each patch.
+func (s *Server) ClusterLeaderboard(ctx context.Context, id string, in *GetResultsArgs, args *ClusterArgs) ([]PatchClusters, error) {
+	if s.Clusters == nil {
+		return nil, errors.New("clusters are not configured")
+	}
+
+	out, err := s.GetResultsFromLeaderboard(ctx, id, in)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	results := make(PUUIDResults)
+	for _, rr := range out {
+		for _, r := range rr {
+			results[r.PUUID] = append(results[r.PUUID], r)
+		}
+	}
+
+	clusters := Cluster(results, args)
+	if err := s.Clusters.PutClusters(ctx, id, clusters); err != nil {
+		return nil, errors.Wrap(err, "put clusters failed")
+	}
+
+	return clusters, err
+}
+
+// GetMeta is a Leaderboard's tier list of Archetypes for a patch, e.g. "9.22".
+// An empty patch is the newest clustered patch. Nil is returned if the patch
+// has not been clustered.
+func (s *Server) GetMeta(ctx context.Context, id, patch string) (*PatchClusters, error) {
+	if s.Clusters == nil {
+		return nil, errors.New("clusters are not configured")
+	}
+
+	clusters, err := s.Clusters.ListClusters(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "list clusters failed")
+	}
+
+	for _, pc := range clusters {
+		pc := pc
+		if patch == "" || pc.Patch == patch {
+			return &pc, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,174 @@
+package leaderboards_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+// board of a trait and units on a patch.
+func board(id, puuid, patch string, placement int, trait string, units ...string) leaderboards.Result {
+	r := leaderboards.Result{
+		MatchID:     id,
+		GameVersion: "Version " + patch + ".1.1",
+		Participant: tft.Participant{
+			PUUID:     puuid,
+			Placement: placement,
+			Traits:    []tft.Trait{{Name: trait, TierCurrent: 2}},
+		},
+	}
+
+	if patch == "" {
+		r.GameVersion = ""
+	}
+
+	for _, u := range units {
+		r.Units = append(r.Units, tft.Unit{CharacterID: u, Tier: 2})
+	}
+
+	return r
+}
+
+// archetypeResults are three Rangers and three Wardens on 9.22, and a
+// Ranger on 9.23, split between two players.
+func archetypeResults() leaderboards.PUUIDResults {
+	return leaderboards.PUUIDResults{
+		puuidOne: {
+			board("NA1_1", puuidOne, "9.22", 1, "Set2_Ranger", "TFT2_Ashe", "TFT2_Kindred"),
+			board("NA1_2", puuidOne, "9.22", 2, "Set2_Ranger", "TFT2_Ashe", "TFT2_Varus"),
+			board("NA1_3", puuidOne, "9.22", 7, "Set2_Warden", "TFT2_Braum", "TFT2_Malphite"),
+			board("NA1_7", puuidOne, "9.23", 4, "Set2_Ranger", "TFT2_Ashe"),
+		},
+		puuidTwo: {
+			board("NA1_4", puuidTwo, "9.22", 3, "Set2_Ranger", "TFT2_Ashe", "TFT2_Kindred"),
+			board("NA1_5", puuidTwo, "9.22", 6, "Set2_Warden", "TFT2_Braum", "TFT2_Nautilus"),
+			board("NA1_6", puuidTwo, "9.22", 8, "Set2_Warden", "TFT2_Braum", "TFT2_Malphite"),
+		},
+	}
+}
+
+func TestCluster(t *testing.T) {
+	out := leaderboards.Cluster(archetypeResults(), &leaderboards.ClusterArgs{K: 2})
+
+	if len(out) != 2 || out[0].Patch != "9.23" || out[1].Patch != "9.22" {
+		t.Fatalf("got %d patches, want 9.23 then 9.22", len(out))
+	}
+
+	pc := out[1]
+	if len(pc.Archetypes) != 2 {
+		t.Fatalf("got %d archetypes, want 2", len(pc.Archetypes))
+	}
+
+	// Rangers finish better, so they're first.
+	want := []struct {
+		name  string
+		tier  string
+		games int
+	}{
+		{name: "Set2_Ranger", tier: "S", games: 3},
+		{name: "Set2_Warden", tier: "D", games: 3},
+	}
+
+	for i, w := range want {
+		a := pc.Archetypes[i]
+		if a.ID != i+1 || a.Name != w.name || a.Tier != w.tier || a.Games != w.games {
+			t.Errorf("archetype %d = %+v, want %s in tier %s with %d games", i+1, a, w.name, w.tier, w.games)
+		}
+	}
+
+	for _, as := range pc.Assignments {
+		wantID := 1
+		switch as.MatchID {
+		case "NA1_3", "NA1_5", "NA1_6":
+			wantID = 2
+		}
+
+		if as.ClusterID != wantID {
+			t.Errorf("%s assigned to %d, want %d", as.MatchID, as.ClusterID, wantID)
+		}
+	}
+}
+
+func TestClusterDeterministic(t *testing.T) {
+	want := leaderboards.Cluster(archetypeResults(), nil)
+
+	for i := 0; i < 10; i++ {
+		// Results arrive in any order.
+		results := archetypeResults()
+		rr := results[puuidTwo]
+		for j, k := 0, len(rr)-1; j < k; j, k = j+1, k-1 {
+			rr[j], rr[k] = rr[k], rr[j]
+		}
+
+		if got := leaderboards.Cluster(results, nil); !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d clustered differently:\n%+v\nwant\n%+v", i, got, want)
+		}
+	}
+}
+
+func TestClusterK(t *testing.T) {
+	tests := []struct {
+		name    string
+		results leaderboards.PUUIDResults
+		k       int
+		want    map[string]int // Key = patch, Value = archetypes
+	}{
+		{name: "no results", want: map[string]int{}},
+		{name: "default k", results: archetypeResults(), want: map[string]int{"9.22": 1, "9.23": 1}},
+		// NA1_1 and NA1_4, and NA1_3 and NA1_6, are the same board.
+		{name: "k above the distinct boards", results: archetypeResults(), k: 10, want: map[string]int{"9.22": 4, "9.23": 1}},
+		{
+			name: "identical boards",
+			results: leaderboards.PUUIDResults{puuidOne: {
+				board("NA1_1", puuidOne, "9.22", 1, "Set2_Ranger", "TFT2_Ashe"),
+				board("NA1_2", puuidOne, "9.22", 2, "Set2_Ranger", "TFT2_Ashe"),
+			}},
+			k:    2,
+			want: map[string]int{"9.22": 1},
+		},
+		{
+			name: "unknown patches and empty boards",
+			results: leaderboards.PUUIDResults{puuidOne: {
+				board("NA1_1", puuidOne, "", 1, "Set2_Ranger", "TFT2_Ashe"),
+				{MatchID: "NA1_2", Participant: tft.Participant{PUUID: puuidOne, Placement: 8}},
+				{MatchID: "NA1_3", Participant: tft.Participant{PUUID: puuidOne, Placement: 7}},
+			}},
+			k:    3,
+			want: map[string]int{"": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := leaderboards.Cluster(tt.results, &leaderboards.ClusterArgs{K: tt.k})
+
+			got := make(map[string]int)
+			for _, pc := range out {
+				got[pc.Patch] = len(pc.Archetypes)
+
+				// Every board is assigned to an archetype.
+				var boards int
+				for _, a := range pc.Archetypes {
+					boards += a.Games
+				}
+
+				if boards != len(pc.Assignments) {
+					t.Errorf("%s: %d boards in archetypes, %d assigned", pc.Patch, boards, len(pc.Assignments))
+				}
+
+				for _, as := range pc.Assignments {
+					if as.ClusterID < 1 || as.ClusterID > len(pc.Archetypes) {
+						t.Errorf("%s: %s assigned to missing archetype %d", pc.Patch, as.MatchID, as.ClusterID)
+					}
+				}
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got archetypes %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
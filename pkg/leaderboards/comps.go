@@ -0,0 +1,200 @@
+package leaderboards
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+// Composition signature limits. Boards are identified by their strongest
+// traits and most invested units, rather than every unit and trait, so
+// variations of a comp group together.
+const (
+	compTraits = 2
+	compCore   = 2
+)
+
+// Composition is a board's signature.
+type Composition struct {
+	// Signature identifies a composition, e.g. "Ranger 4 + Inferno 3 / Ashe, Kindred".
+	Signature string `json:"signature"`
+	// Traits are the strongest active traits and their unit counts, e.g. "Ranger 4".
+	Traits []string `json:"traits"`
+	// Core units hold the most items and stars.
+	Core []string `json:"core"`
+}
+
+// CompStats are a composition's results.
+type CompStats struct {
+	Composition
+	Games         int     `json:"games"`
+	Wins          int     `json:"wins"`
+	TopFours      int     `json:"topFours"`
+	AverageFinish float32 `json:"averageFinish"`
+	TopFourRate   float32 `json:"topFourRate"`
+	// Frequency is the share of games played with the composition.
+	Frequency float32 `json:"frequency"`
+}
+
+// CompReport of each player's and the board's compositions, most played first.
+type CompReport struct {
+	Players map[string][]CompStats `json:"players"` // Key = Summoner.Name
+	Board   []CompStats            `json:"board"`
+}
+
+// CompositionOf a result's final board.
+func CompositionOf(r Result) Composition {
+	var traits []tft.Trait
+	for _, t := range r.Traits {
+		if t.TierCurrent > 0 {
+			traits = append(traits, t)
+		}
+	}
+
+	sort.SliceStable(traits, func(i, j int) bool {
+		if traits[i].TierCurrent != traits[j].TierCurrent {
+			return traits[i].TierCurrent > traits[j].TierCurrent
+		}
+
+		if traits[i].NumUnits != traits[j].NumUnits {
+			return traits[i].NumUnits > traits[j].NumUnits
+		}
+
+		return traits[i].Name < traits[j].Name
+	})
+
+	units := make([]tft.Unit, len(r.Units))
+	copy(units, r.Units)
+
+	sort.SliceStable(units, func(i, j int) bool {
+		ii, ij := units[i].ItemCount(), units[j].ItemCount()
+		if ii != ij {
+			return ii > ij
+		}
+
+		if units[i].Tier != units[j].Tier {
+			return units[i].Tier > units[j].Tier
+		}
+
+		if units[i].Rarity != units[j].Rarity {
+			return units[i].Rarity > units[j].Rarity
+		}
+
+		return units[i].CharacterID < units[j].CharacterID
+	})
+
+	var c Composition
+	for i := 0; i < len(traits) && i < compTraits; i++ {
+		c.Traits = append(c.Traits, fmt.Sprintf("%s %d", traitName(traits[i]), traits[i].NumUnits))
+	}
+
+	for i := 0; i < len(units) && i < compCore; i++ {
+		c.Core = append(c.Core, unitName(units[i]))
+	}
+
+	// Core units are ordered by name so the signature doesn't depend on items.
+	core := make([]string, len(c.Core))
+	copy(core, c.Core)
+	sort.Strings(core)
+
+	c.Signature = strings.Join(c.Traits, " + ") + " / " + strings.Join(core, ", ")
+
+	return c
+}
+
+// CalculateComps of a set of results, most played first.
+func CalculateComps(rr []Result) []CompStats {
+	byComp := make(map[string][]Result) // Key = Composition.Signature
+	comps := make(map[string]Composition)
+	for _, r := range rr {
+		c := CompositionOf(r)
+		byComp[c.Signature] = append(byComp[c.Signature], r)
+		comps[c.Signature] = c
+	}
+
+	var out []CompStats
+	for sig, results := range byComp {
+		stat := CalculateStats(results, nil)
+		out = append(out, CompStats{
+			Composition:   comps[sig],
+			Games:         stat.Games,
+			Wins:          stat.Wins,
+			TopFours:      stat.TopFours,
+			AverageFinish: stat.AverageFinish,
+			TopFourRate:   stat.TopFourRate,
+			Frequency:     float32(stat.Games) / float32(len(rr)),
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Games != out[j].Games {
+			return out[i].Games > out[j].Games
+		}
+
+		if out[i].AverageFinish != out[j].AverageFinish {
+			return out[i].AverageFinish < out[j].AverageFinish
+		}
+
+		return out[i].Signature < out[j].Signature
+	})
+
+	return out
+}
+
+// CalculateCompReport for each player and across every player.
+func CalculateCompReport(results NameResults) *CompReport {
+	report := &CompReport{
+		Players: make(map[string][]CompStats),
+	}
+
+	var all []Result
+	for name, rr := range results {
+		report.Players[name] = CalculateComps(rr)
+		all = append(all, rr...)
+	}
+
+	report.Board = CalculateComps(all)
+
+	return report
+}
+
+// GetComps of Summoners by name.
+func (s *Server) GetComps(ctx context.Context, names []string, in *GetResultsArgs) (*CompReport, error) {
+	out, err := s.GetResultsFromNames(ctx, names, in)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	return CalculateCompReport(out), err
+}
+
+// GetBoardComps of a Leaderboard's members.
+func (s *Server) GetBoardComps(ctx context.Context, id string, in *GetResultsArgs) (*CompReport, error) {
+	out, err := s.GetResultsFromLeaderboard(ctx, id, in)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	return CalculateCompReport(out), err
+}
+
+// traitName prefers static data's display name, e.g. Ranger over Set2_Ranger.
+func traitName(t tft.Trait) string {
+	if t.DisplayName != "" {
+		return t.DisplayName
+	}
+
+	return t.Name
+}
+
+// unitName prefers static data's display name, e.g. Ashe over TFT2_Ashe.
+func unitName(u tft.Unit) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+
+	return u.CharacterID
+}
@@ -0,0 +1,170 @@
+package leaderboards
+
+import (
+	"sort"
+)
+
+// Form defaults.
+const (
+	// FormGames in a form line, and in each half of a trend.
+	FormGames = 5
+	// HotStreakGames in a row in the top four put a player on a hot streak.
+	HotStreakGames = 3
+	// trendThreshold is the change in average placement that counts as a trend.
+	trendThreshold = 0.5
+)
+
+// Trends of average placement.
+const (
+	TrendImproving = "improving"
+	TrendDeclining = "declining"
+	TrendSteady    = "steady"
+)
+
+// Streaks of consecutive results. Current streaks count back from the
+// newest game, so at most one of CurrentTopFour and CurrentBottomFour is set.
+type Streaks struct {
+	CurrentWin        int `json:"currentWin"`
+	CurrentTopFour    int `json:"currentTopFour"`
+	CurrentBottomFour int `json:"currentBottomFour"`
+	LongestWin        int `json:"longestWin"`
+	LongestTopFour    int `json:"longestTopFour"`
+	LongestBottomFour int `json:"longestBottomFour"`
+	// HotStreak is set after HotStreakGames top fours in a row.
+	HotStreak bool `json:"hotStreak"`
+}
+
+// Form is a player's recent results.
+type Form struct {
+	// Line is the placements of the last FormGames games, newest first.
+	Line        []int   `json:"line"`
+	LineAverage float32 `json:"lineAverage"`
+	// Trend compares the last FormGames games to the FormGames before them.
+	Trend string `json:"trend"`
+	// TrendDelta is the previous average placement minus the recent one,
+	// so it's positive when improving.
+	TrendDelta float32 `json:"trendDelta"`
+}
+
+// newestFirst copies results sorted from newest to oldest.
+func newestFirst(rr []Result) []Result {
+	sorted := make([]Result, len(rr))
+	copy(sorted, rr)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.After(sorted[j].StartedAt)
+	})
+
+	return sorted
+}
+
+// CalculateStreaks of a set of results.
+func CalculateStreaks(rr []Result) Streaks {
+	var (
+		s                        Streaks
+		win, topFour, bottomFour int
+		// Current streaks end at the first game that breaks them.
+		currentWin, currentTopFour, currentBottomFour = true, true, true
+	)
+
+	for _, r := range newestFirst(rr) {
+		if r.Placement == 1 {
+			win++
+		} else {
+			win = 0
+			currentWin = false
+		}
+
+		if r.Placement <= 4 {
+			topFour++
+			bottomFour = 0
+			currentBottomFour = false
+		} else {
+			bottomFour++
+			topFour = 0
+			currentTopFour = false
+		}
+
+		if currentWin {
+			s.CurrentWin = win
+		}
+
+		if currentTopFour {
+			s.CurrentTopFour = topFour
+		}
+
+		if currentBottomFour {
+			s.CurrentBottomFour = bottomFour
+		}
+
+		s.LongestWin = maxInt(s.LongestWin, win)
+		s.LongestTopFour = maxInt(s.LongestTopFour, topFour)
+		s.LongestBottomFour = maxInt(s.LongestBottomFour, bottomFour)
+	}
+
+	s.HotStreak = s.CurrentTopFour >= HotStreakGames
+
+	return s
+}
+
+// CalculateForm of a set of results.
+func CalculateForm(rr []Result) Form {
+	sorted := newestFirst(rr)
+
+	f := Form{
+		Line:  []int{},
+		Trend: TrendSteady,
+	}
+
+	recent := sorted
+	if len(recent) > FormGames {
+		recent = recent[:FormGames]
+	}
+
+	for _, r := range recent {
+		f.Line = append(f.Line, r.Placement)
+	}
+
+	f.LineAverage = averagePlacement(recent)
+
+	previous := sorted[len(recent):]
+	if len(previous) > FormGames {
+		previous = previous[:FormGames]
+	}
+
+	if len(recent) == 0 || len(previous) == 0 {
+		return f
+	}
+
+	f.TrendDelta = averagePlacement(previous) - f.LineAverage
+
+	switch {
+	case f.TrendDelta >= trendThreshold:
+		f.Trend = TrendImproving
+	case f.TrendDelta <= -trendThreshold:
+		f.Trend = TrendDeclining
+	}
+
+	return f
+}
+
+func averagePlacement(rr []Result) float32 {
+	if len(rr) == 0 {
+		return 0
+	}
+
+	var sum int
+	for _, r := range rr {
+		sum += r.Placement
+	}
+
+	return float32(sum) / float32(len(rr))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
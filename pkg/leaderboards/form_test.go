@@ -0,0 +1,151 @@
+package leaderboards_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+// placed results, newest first, returned oldest first.
+func placed(placements ...int) []leaderboards.Result {
+	newest := time.Date(2019, 11, 4, 12, 0, 0, 0, time.UTC)
+
+	rr := make([]leaderboards.Result, len(placements))
+	for i, p := range placements {
+		rr[len(rr)-1-i] = leaderboards.Result{
+			MatchID:     fmt.Sprintf("NA1_%d", 1000-i),
+			StartedAt:   newest.Add(-time.Duration(i) * time.Hour),
+			Participant: tft.Participant{PUUID: puuidOne, Placement: p},
+		}
+	}
+
+	return rr
+}
+
+func TestCalculateStreaks(t *testing.T) {
+	tests := []struct {
+		name       string
+		placements []int // Newest first
+		want       leaderboards.Streaks
+	}{
+		{
+			name: "no games",
+		},
+		{
+			name:       "wins continue",
+			placements: []int{1, 1, 1},
+			want:       leaderboards.Streaks{CurrentWin: 3, CurrentTopFour: 3, LongestWin: 3, LongestTopFour: 3, HotStreak: true},
+		},
+		{
+			name:       "win streak ended",
+			placements: []int{3, 1, 1, 6},
+			want:       leaderboards.Streaks{CurrentTopFour: 3, LongestWin: 2, LongestTopFour: 3, LongestBottomFour: 1, HotStreak: true},
+		},
+		{
+			name:       "top four streak ended",
+			placements: []int{5, 2, 4, 3, 1},
+			want:       leaderboards.Streaks{CurrentBottomFour: 1, LongestWin: 1, LongestTopFour: 4, LongestBottomFour: 1},
+		},
+		{
+			name:       "top four short of a hot streak",
+			placements: []int{2, 4, 8, 7, 6},
+			want:       leaderboards.Streaks{CurrentTopFour: 2, LongestTopFour: 2, LongestBottomFour: 3},
+		},
+		{
+			name:       "bottom four continue",
+			placements: []int{8, 5, 1},
+			want:       leaderboards.Streaks{CurrentBottomFour: 2, LongestWin: 1, LongestTopFour: 1, LongestBottomFour: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaderboards.CalculateStreaks(placed(tt.placements...)); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateForm(t *testing.T) {
+	tests := []struct {
+		name       string
+		placements []int // Newest first
+		wantLine   []int
+		wantAvg    float32
+		wantTrend  string
+		wantDelta  float32
+	}{
+		{
+			name:      "no games",
+			wantLine:  []int{},
+			wantTrend: leaderboards.TrendSteady,
+		},
+		{
+			name:       "shorter than the form line",
+			placements: []int{2, 6, 1},
+			wantLine:   []int{2, 6, 1},
+			wantAvg:    3,
+			wantTrend:  leaderboards.TrendSteady,
+		},
+		{
+			name:       "exactly the form line",
+			placements: []int{1, 2, 3, 4, 5},
+			wantLine:   []int{1, 2, 3, 4, 5},
+			wantAvg:    3,
+			wantTrend:  leaderboards.TrendSteady,
+		},
+		{
+			name:       "improving on a short previous line",
+			placements: []int{1, 2, 1, 2, 4, 8},
+			wantLine:   []int{1, 2, 1, 2, 4},
+			wantAvg:    2,
+			wantTrend:  leaderboards.TrendImproving,
+			wantDelta:  6,
+		},
+		{
+			name:       "declining",
+			placements: []int{8, 7, 6, 5, 4, 3, 2, 1, 2, 3},
+			wantLine:   []int{8, 7, 6, 5, 4},
+			wantAvg:    6,
+			wantTrend:  leaderboards.TrendDeclining,
+			wantDelta:  -3.8,
+		},
+		{
+			name:       "steady within the threshold",
+			placements: []int{4, 4, 4, 4, 4, 4, 4, 4, 4, 2},
+			wantLine:   []int{4, 4, 4, 4, 4},
+			wantAvg:    4,
+			wantTrend:  leaderboards.TrendSteady,
+			wantDelta:  -0.4,
+		},
+		{
+			name:       "games beyond both lines are ignored",
+			placements: []int{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 8, 8},
+			wantLine:   []int{4, 4, 4, 4, 4},
+			wantAvg:    4,
+			wantTrend:  leaderboards.TrendSteady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := leaderboards.CalculateForm(placed(tt.placements...))
+
+			if fmt.Sprint(got.Line) != fmt.Sprint(tt.wantLine) || got.Line == nil {
+				t.Errorf("line = %v, want %v", got.Line, tt.wantLine)
+			}
+
+			if !approx(got.LineAverage, tt.wantAvg) {
+				t.Errorf("line average = %v, want %v", got.LineAverage, tt.wantAvg)
+			}
+
+			if got.Trend != tt.wantTrend || !approx(got.TrendDelta, tt.wantDelta) {
+				t.Errorf("trend = %s (%v), want %s (%v)", got.Trend, got.TrendDelta, tt.wantTrend, tt.wantDelta)
+			}
+		})
+	}
+}
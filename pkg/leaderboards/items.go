@@ -0,0 +1,247 @@
+package leaderboards
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/alee792/teamfit/pkg/staticdata"
+)
+
+// maxCarriers reported per item.
+const maxCarriers = 3
+
+// ItemStats are an item's builds and the results of games it was built in.
+type ItemStats struct {
+	// Item is the item's name, or its ID without static data.
+	Item      string `json:"item"`
+	Completed bool   `json:"completed"`
+	// Builds counts every copy of the item, so may exceed Games.
+	Builds int `json:"builds"`
+	Games  int `json:"games"`
+	// Frequency is the share of games the item was built in.
+	Frequency     float32 `json:"frequency"`
+	AverageFinish float32 `json:"averageFinish"`
+	TopFourRate   float32 `json:"topFourRate"`
+	// Carriers are the units that held the item most, best first.
+	Carriers []CarrierStats `json:"carriers"`
+}
+
+// CarrierStats are a unit's results holding an item.
+type CarrierStats struct {
+	Unit          string  `json:"unit"`
+	Builds        int     `json:"builds"`
+	AverageFinish float32 `json:"averageFinish"`
+}
+
+// ItemBreakdown of a player's or board's items.
+type ItemBreakdown struct {
+	// Completed and Components count item builds across every game.
+	Completed  int         `json:"completed"`
+	Components int         `json:"components"`
+	Items      []ItemStats `json:"items"`
+}
+
+// ItemReport of each player's and the board's items, most built first.
+type ItemReport struct {
+	Players map[string]*ItemBreakdown `json:"players"` // Key = Summoner.Name
+	Board   *ItemBreakdown            `json:"board"`
+}
+
+// builtItem on a unit.
+type builtItem struct {
+	name      string
+	completed bool
+}
+
+// itemsOf a result's units, named with its match's static data if any.
+// Riot may send both Items and ItemNames for the same items, so ItemNames
+// are preferred when present.
+func itemsOf(b *staticdata.Bundle, r Result) map[string][]builtItem {
+	out := make(map[string][]builtItem) // Key = unit name
+	for _, u := range r.Units {
+		unit := unitName(u)
+		if len(u.ItemNames) > 0 {
+			for _, apiName := range u.ItemNames {
+				name := apiName
+				if b != nil {
+					if i, ok := b.ItemByName(apiName); ok && i.Name != "" {
+						name = i.Name
+					}
+				}
+
+				out[unit] = append(out[unit], builtItem{
+					name:      name,
+					completed: staticdata.ItemNameComponents(b, apiName) > 1,
+				})
+			}
+
+			continue
+		}
+
+		for _, id := range u.Items {
+			name := strconv.Itoa(id)
+			if b != nil {
+				if i, ok := b.Item(id); ok && i.Name != "" {
+					name = i.Name
+				}
+			}
+
+			out[unit] = append(out[unit], builtItem{
+				name:      name,
+				completed: staticdata.ItemComponents(b, id) > 1,
+			})
+		}
+	}
+
+	return out
+}
+
+// CalculateItems of a set of results. Items are named and classified
+// with static, which may be nil.
+func CalculateItems(rr []Result, static *staticdata.Store) *ItemBreakdown {
+	type carrier struct {
+		builds   int
+		finishes int
+	}
+
+	type tally struct {
+		completed bool
+		builds    int
+		games     int
+		finishes  int
+		topFours  int
+		carriers  map[string]*carrier
+	}
+
+	var (
+		breakdown = &ItemBreakdown{}
+		tallies   = make(map[string]*tally) // Key = item name
+	)
+
+	for _, r := range rr {
+		built := make(map[string]bool)
+		for unit, items := range itemsOf(static.Lookup(r.Set, r.GameVersion), r) {
+			for _, i := range items {
+				t, ok := tallies[i.name]
+				if !ok {
+					t = &tally{completed: i.completed, carriers: make(map[string]*carrier)}
+					tallies[i.name] = t
+				}
+
+				if i.completed {
+					breakdown.Completed++
+				} else {
+					breakdown.Components++
+				}
+
+				t.builds++
+
+				c, ok := t.carriers[unit]
+				if !ok {
+					c = &carrier{}
+					t.carriers[unit] = c
+				}
+
+				c.builds++
+				c.finishes += r.Placement
+
+				if !built[i.name] {
+					built[i.name] = true
+					t.games++
+					t.finishes += r.Placement
+					if r.Placement <= 4 {
+						t.topFours++
+					}
+				}
+			}
+		}
+	}
+
+	for name, t := range tallies {
+		stat := ItemStats{
+			Item:          name,
+			Completed:     t.completed,
+			Builds:        t.builds,
+			Games:         t.games,
+			Frequency:     float32(t.games) / float32(len(rr)),
+			AverageFinish: float32(t.finishes) / float32(t.games),
+			TopFourRate:   float32(t.topFours) / float32(t.games),
+		}
+
+		for unit, c := range t.carriers {
+			stat.Carriers = append(stat.Carriers, CarrierStats{
+				Unit:          unit,
+				Builds:        c.builds,
+				AverageFinish: float32(c.finishes) / float32(c.builds),
+			})
+		}
+
+		sort.Slice(stat.Carriers, func(i, j int) bool {
+			ci, cj := stat.Carriers[i], stat.Carriers[j]
+			if ci.Builds != cj.Builds {
+				return ci.Builds > cj.Builds
+			}
+
+			if ci.AverageFinish != cj.AverageFinish {
+				return ci.AverageFinish < cj.AverageFinish
+			}
+
+			return ci.Unit < cj.Unit
+		})
+
+		if len(stat.Carriers) > maxCarriers {
+			stat.Carriers = stat.Carriers[:maxCarriers]
+		}
+
+		breakdown.Items = append(breakdown.Items, stat)
+	}
+
+	sort.Slice(breakdown.Items, func(i, j int) bool {
+		ii, ij := breakdown.Items[i], breakdown.Items[j]
+		if ii.Builds != ij.Builds {
+			return ii.Builds > ij.Builds
+		}
+
+		return ii.Item < ij.Item
+	})
+
+	return breakdown
+}
+
+// CalculateItemReport for each player and across every player.
+func CalculateItemReport(results NameResults, static *staticdata.Store) *ItemReport {
+	report := &ItemReport{
+		Players: make(map[string]*ItemBreakdown),
+	}
+
+	var all []Result
+	for name, rr := range results {
+		report.Players[name] = CalculateItems(rr, static)
+		all = append(all, rr...)
+	}
+
+	report.Board = CalculateItems(all, static)
+
+	return report
+}
+
+// GetItems built by Summoners by name.
+func (s *Server) GetItems(ctx context.Context, names []string, in *GetResultsArgs) (*ItemReport, error) {
+	out, err := s.GetResultsFromNames(ctx, names, in)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	return CalculateItemReport(out, s.Static), err
+}
+
+// GetBoardItems built by a Leaderboard's members.
+func (s *Server) GetBoardItems(ctx context.Context, id string, in *GetResultsArgs) (*ItemReport, error) {
+	out, err := s.GetResultsFromLeaderboard(ctx, id, in)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	return CalculateItemReport(out, s.Static), err
+}
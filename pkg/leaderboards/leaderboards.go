@@ -5,38 +5,78 @@ import (
 	"context"
 	"time"
 
+	"github.com/alee792/teamfit/pkg/staticdata"
 	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
 )
 
 // Server of leaderboards and related stats.
 type Server struct {
 	API     API
 	Storage Storage
+	// Parallelism of API calls made by GetResults.
+	// Defaults to tft.DefaultParallelism.
+	Parallelism int
+	// Static enriches results with display names, costs and icons.
+	// Optional.
+	Static *staticdata.Store
+	// Ratings persists rating history. Optional.
+	Ratings RatingStore
+	// Snapshots persists LeagueSnapshots. Optional.
+	Snapshots SnapshotStore
+	// Clusters persists composition clusters. Optional.
+	Clusters ClusterStore
+	// Achievements are awarded by UpdateAwards and persisted in Awards. Optional.
+	Achievements *Achievements
+	Awards       AwardStore
 }
 
+// ErrNotFound is returned for Leaderboards that don't exist.
+// Match it with errors.Is.
+var ErrNotFound = errors.New("not found")
+
 // Storage persists Leaderboards.
+// Leaderboards that don't exist are reported by ErrNotFound.
 type Storage interface {
 	CreateLeaderboard(ctx context.Context, board *Leaderboard) (*Leaderboard, error)
 	GetLeaderboard(ctx context.Context, id string) (*Leaderboard, error)
-	// UpdateLeaderboard(ctx context.Context, id string, board *Leaderboard) (*Leaderboard, error)
+	UpdateLeaderboard(ctx context.Context, id string, board *Leaderboard) (*Leaderboard, error)
+	ListLeaderboards(ctx context.Context) ([]*Leaderboard, error)
 	// DeleteLeaderboard(ctx context.Context, id string) (*Leaderboard, error)
 }
 
+// MatchStore persists Riot matches, which never change once played.
+type MatchStore interface {
+	// GetMatch returns nil if the match has not been stored.
+	GetMatch(ctx context.Context, id string) (*tft.Match, error)
+	PutMatch(ctx context.Context, id string, m *tft.Match) error
+}
+
 // API for TFT
 type API interface {
 	GetSummoner(ctx context.Context, name string) (*tft.Summoner, error)
 	GetSummoners(ctx context.Context, names []string) ([]tft.Summoner, error)
+	GetSummonerByPUUID(ctx context.Context, puuid string) (*tft.Summoner, error)
+	GetSummonerByID(ctx context.Context, summonerID string) (*tft.Summoner, error)
+	GetAccountByRiotID(ctx context.Context, gameName, tagLine string) (*tft.Account, error)
+	GetAccountByPUUID(ctx context.Context, puuid string) (*tft.Account, error)
 	ListMatches(ctx context.Context, in *tft.ListMatchesRequest) (*tft.ListMatchesResponse, error)
 	GetMatch(ctx context.Context, in *tft.GetMatchRequest) (*tft.GetMatchResponse, error)
 	GetMostRecentMatch(ctx context.Context, summoner string) (*tft.Match, error)
-	GetLeagueEntry(ctx context.Context, summonerID string) (*tft.LeagueEntry, error)
+	GetLeagueEntries(ctx context.Context, summonerID string) ([]tft.LeagueEntry, error)
+	GetApexLeague(ctx context.Context, tier string) (*tft.LeagueList, error)
+	GetLeague(ctx context.Context, leagueID string) (*tft.LeagueList, error)
+	ListLeagueEntries(ctx context.Context, in *tft.ListLeagueEntriesRequest) (*tft.ListLeagueEntriesResponse, error)
+	GetRatedLadder(ctx context.Context, queueType string) ([]tft.TopRatedLadderEntry, error)
 }
 
 // Leaderboard is a statless group of Summoners.
 type Leaderboard struct {
 	ID        string              `json:"id"`
 	Name      string              `json:"name"`
-	Summoners map[string]Summoner // Key = Summoner.Name `json:"summoners"`
+	Summoners map[string]Summoner // Key = Summoner.PUUID `json:"summoners"`
+	// Queues counted by default, e.g. tft.QueueRanked. Empty counts every queue.
+	Queues []int `json:"queues,omitempty"`
 }
 
 // SummonerID ties a PUUID to a Name.
@@ -49,3 +89,41 @@ type SummonerID struct {
 func UnixMS(ms int) time.Time {
 	return time.Unix(int64(ms/1000), 0)
 }
+
+func (s *Server) parallelism() int {
+	if s.Parallelism < 1 {
+		return tft.DefaultParallelism
+	}
+
+	return s.Parallelism
+}
+
+// IsPartial reports whether err is a *tft.BatchError, meaning results
+// were returned for everything except the failed items it describes.
+func IsPartial(err error) bool {
+	var batch *tft.BatchError
+	return errors.As(err, &batch)
+}
+
+// mergeFailures collects a *tft.BatchError's failures and returns any other error.
+func mergeFailures(failures map[string]error, err error) error {
+	var batch *tft.BatchError
+	if !errors.As(err, &batch) {
+		return err
+	}
+
+	for k, v := range batch.Errors {
+		failures[k] = v
+	}
+
+	return nil
+}
+
+// batchError reports failures, if any.
+func batchError(failures map[string]error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &tft.BatchError{Errors: failures}
+}
@@ -0,0 +1,327 @@
+package leaderboards
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alee792/teamfit/internal/fanout"
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// Metric Stats can be ranked by.
+type Metric string
+
+// Metrics supported by Rank.
+const (
+	MetricAverageFinish     Metric = "averageFinish"
+	MetricMedianFinish      Metric = "medianFinish"
+	MetricTopFourRate       Metric = "topFourRate"
+	MetricTopFours          Metric = "topFours"
+	MetricWinRate           Metric = "winRate"
+	MetricWins              Metric = "wins"
+	MetricDamageDealt       Metric = "damageDealt"
+	MetricPlayersEliminated Metric = "playersEliminated"
+	MetricAverageBoardGold  Metric = "averageBoardGold"
+	MetricGames             Metric = "games"
+	// MetricForm ranks by average placement over the last FormGames games.
+	MetricForm Metric = "form"
+	// MetricStreak ranks by current top four streak.
+	MetricStreak Metric = "streak"
+	// MetricLP ranks by ladder position, i.e. LadderLP.
+	MetricLP Metric = "lp"
+)
+
+// DefaultMetric ranks players by average finish.
+const DefaultMetric = MetricAverageFinish
+
+// metricValues of a RankedEntry. Lower is better when ascending.
+var metricValues = map[Metric]struct {
+	value     func(e *RankedEntry) float64
+	ascending bool
+}{
+	MetricAverageFinish:     {func(e *RankedEntry) float64 { return float64(e.Stats.AverageFinish) }, true},
+	MetricMedianFinish:      {func(e *RankedEntry) float64 { return float64(e.Stats.MedianFinish) }, true},
+	MetricTopFourRate:       {func(e *RankedEntry) float64 { return float64(e.Stats.TopFourRate) }, false},
+	MetricTopFours:          {func(e *RankedEntry) float64 { return float64(e.Stats.TopFours) }, false},
+	MetricWinRate:           {func(e *RankedEntry) float64 { return float64(e.Stats.WinRate) }, false},
+	MetricWins:              {func(e *RankedEntry) float64 { return float64(e.Stats.Wins) }, false},
+	MetricDamageDealt:       {func(e *RankedEntry) float64 { return float64(e.Stats.DamageDealt) }, false},
+	MetricPlayersEliminated: {func(e *RankedEntry) float64 { return float64(e.Stats.PlayersEliminated) }, false},
+	MetricAverageBoardGold:  {func(e *RankedEntry) float64 { return float64(e.Stats.AverageBoardGold) }, false},
+	MetricGames:             {func(e *RankedEntry) float64 { return float64(e.Stats.Games) }, false},
+	MetricForm:              {func(e *RankedEntry) float64 { return float64(e.Stats.Form.LineAverage) }, true},
+	MetricStreak:            {func(e *RankedEntry) float64 { return float64(e.Stats.Streaks.CurrentTopFour) }, false},
+	MetricLP:                {func(e *RankedEntry) float64 { return float64(ladderPoints(e.League)) }, false},
+}
+
+// ParseMetric validates a case insensitive Metric, e.g. "topFourRate".
+func ParseMetric(s string) (Metric, error) {
+	for m := range metricValues {
+		if strings.EqualFold(string(m), strings.TrimSpace(s)) {
+			return m, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown metric: %q", s)
+}
+
+// RankArgs configure how Stats are ranked.
+type RankArgs struct {
+	// Metric to rank by. Defaults to DefaultMetric.
+	Metric Metric
+	// TieBreakers are compared in order when Metric is tied.
+	// Defaults to average finish, then games played.
+	TieBreakers []Metric
+	// MinGames a player must have played to be ranked.
+	MinGames int
+	// QueueType of the League used by MetricLP. Defaults to tft.QueueTypeRanked.
+	QueueType string
+}
+
+// RankedEntry is a player's position on a ranking.
+type RankedEntry struct {
+	// Rank starts at 1. Tied players share a Rank.
+	// Players without enough games have a Rank of 0.
+	Rank  int    `json:"rank"`
+	Name  string `json:"name"`
+	Stats Stats  `json:"stats"`
+	// League is only set when ranking by MetricLP.
+	League *tft.LeagueEntry `json:"-"`
+	Tier   string           `json:"tier,omitempty"`
+	// Division is the League's rank, e.g. II.
+	Division     string `json:"division,omitempty"`
+	LeaguePoints int    `json:"leaguePoints,omitempty"`
+}
+
+// GetRankingArgs is a request for ranked Stats.
+type GetRankingArgs struct {
+	GetStatsArgs
+	RankArgs
+}
+
+// GetRanking of Summoners by name, ordered by rank.
+func (s *Server) GetRanking(ctx context.Context, names []string, in *GetRankingArgs) ([]RankedEntry, error) {
+	failures := make(map[string]error)
+
+	stats, err := s.GetStats(ctx, names, &in.GetStatsArgs)
+	if err := mergeFailures(failures, err); err != nil {
+		return nil, err
+	}
+
+	var leagues map[string]tft.LeagueEntry
+	if in.RankArgs.uses(MetricLP) {
+		leagues, err = s.getLeagues(ctx, stats, in.RankArgs.queueType())
+		if err := mergeFailures(failures, err); err != nil {
+			return nil, err
+		}
+	}
+
+	return Rank(stats, leagues, &in.RankArgs), batchError(failures)
+}
+
+// getLeagues of each named player in a queue.
+func (s *Server) getLeagues(ctx context.Context, stats map[string]Stats, queueType string) (map[string]tft.LeagueEntry, error) {
+	var names []string
+	for n := range stats {
+		names = append(names, n)
+	}
+
+	smnrs := make([]*Summoner, len(names))
+	errs := fanout.Do(ctx, len(names), s.parallelism(), func(ctx context.Context, i int) error {
+		smnr, err := s.GetSummoner(ctx, names[i])
+		if err != nil {
+			return err
+		}
+
+		smnrs[i] = smnr
+
+		return nil
+	})
+
+	var (
+		leagues  = make(map[string]tft.LeagueEntry)
+		failures = make(map[string]error)
+	)
+
+	for i, err := range errs {
+		if err != nil {
+			failures[names[i]] = errors.Wrap(err, "failed to get league")
+			continue
+		}
+
+		if le, ok := smnrs[i].Leagues[queueType]; ok {
+			leagues[names[i]] = le
+		}
+	}
+
+	return leagues, batchError(failures)
+}
+
+// Rank Stats by name. leagues are only required for MetricLP.
+func Rank(stats map[string]Stats, leagues map[string]tft.LeagueEntry, in *RankArgs) []RankedEntry {
+	var (
+		ranked     []RankedEntry
+		ineligible []RankedEntry
+	)
+
+	for name, stat := range stats {
+		e := RankedEntry{
+			Name:  name,
+			Stats: stat,
+		}
+
+		if le, ok := leagues[name]; ok {
+			le := le
+			e.League = &le
+			e.Tier, e.Division, e.LeaguePoints = le.Tier, le.Rank, le.LeaguePoints
+		}
+
+		if stat.Games < in.MinGames || stat.Games == 0 {
+			ineligible = append(ineligible, e)
+			continue
+		}
+
+		ranked = append(ranked, e)
+	}
+
+	metrics := in.metrics()
+	sort.Slice(ranked, func(i, j int) bool {
+		if c := compareEntries(&ranked[i], &ranked[j], metrics); c != 0 {
+			return c < 0
+		}
+
+		return ranked[i].Name < ranked[j].Name
+	})
+
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+		if i > 0 && compareEntries(&ranked[i-1], &ranked[i], metrics) == 0 {
+			ranked[i].Rank = ranked[i-1].Rank
+		}
+	}
+
+	sort.Slice(ineligible, func(i, j int) bool {
+		return ineligible[i].Name < ineligible[j].Name
+	})
+
+	return append(ranked, ineligible...)
+}
+
+// compareEntries by metrics in order. Better entries are less.
+func compareEntries(a, b *RankedEntry, metrics []Metric) int {
+	for _, m := range metrics {
+		mv, ok := metricValues[m]
+		if !ok {
+			continue
+		}
+
+		x, y := mv.value(a), mv.value(b)
+		if x == y {
+			continue
+		}
+
+		if (x < y) == mv.ascending {
+			return -1
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+// metrics to compare, the Metric followed by its tie breakers.
+func (in *RankArgs) metrics() []Metric {
+	metric := in.Metric
+	if metric == "" {
+		metric = DefaultMetric
+	}
+
+	tieBreakers := in.TieBreakers
+	if len(tieBreakers) == 0 {
+		tieBreakers = []Metric{MetricAverageFinish, MetricGames}
+	}
+
+	return append([]Metric{metric}, tieBreakers...)
+}
+
+func (in *RankArgs) uses(m Metric) bool {
+	for _, mm := range in.metrics() {
+		if mm == m {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (in *RankArgs) queueType() string {
+	if in.QueueType == "" {
+		return tft.QueueTypeRanked
+	}
+
+	return in.QueueType
+}
+
+// tierOrder of the ranked ladder, lowest first.
+var tierOrder = []string{
+	tft.TierIron,
+	tft.TierBronze,
+	tft.TierSilver,
+	tft.TierGold,
+	tft.TierPlatinum,
+	tft.TierEmerald,
+	tft.TierDiamond,
+	tft.TierMaster,
+	tft.TierGrandmaster,
+	tft.TierChallenger,
+}
+
+// divisionOrder within a tier, lowest first.
+var divisionOrder = []string{
+	tft.DivisionIV,
+	tft.DivisionIII,
+	tft.DivisionII,
+	tft.DivisionI,
+}
+
+// LadderLP is an entry's LP counted from the bottom of Iron IV. Divisions are
+// 100 LP apart, while Master, Grandmaster and Challenger share a single LP pool.
+func LadderLP(tier, rank string, lp int) int {
+	for i, t := range tierOrder {
+		if t != tier {
+			continue
+		}
+
+		if tft.IsApexTier(t) {
+			return apexLP + lp
+		}
+
+		base := i * len(divisionOrder) * 100
+		for j, d := range divisionOrder {
+			if d == rank {
+				base += j * 100
+			}
+		}
+
+		return base + lp
+	}
+
+	return lp
+}
+
+// apexLP is Master's LadderLP at 0 LP.
+const apexLP = 7 * 400
+
+// ladderPoints orders League entries by LadderLP.
+// Unranked players have no points.
+func ladderPoints(le *tft.LeagueEntry) int {
+	if le == nil {
+		return -1
+	}
+
+	return LadderLP(le.Tier, le.Rank, le.LeaguePoints)
+}
@@ -0,0 +1,106 @@
+package leaderboards_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+func TestRank(t *testing.T) {
+	stats := map[string]leaderboards.Stats{
+		"Ada": {
+			Games: 10, Wins: 2, TopFours: 7, AverageFinish: 3, MedianFinish: 3, TopFourRate: 0.7, WinRate: 0.2,
+			DamageDealt: 500, PlayersEliminated: 10, AverageBoardGold: 40,
+			Form: leaderboards.Form{LineAverage: 2.5}, Streaks: leaderboards.Streaks{CurrentTopFour: 3},
+		},
+		"Bo": {
+			Games: 8, Wins: 2, TopFours: 4, AverageFinish: 4, MedianFinish: 4.5, TopFourRate: 0.5, WinRate: 0.25,
+			DamageDealt: 700, PlayersEliminated: 5, AverageBoardGold: 50,
+			Form: leaderboards.Form{LineAverage: 3}, Streaks: leaderboards.Streaks{CurrentTopFour: 1},
+		},
+		"Cy": {
+			Games: 12, Wins: 1, TopFours: 9, AverageFinish: 3, MedianFinish: 2.5, TopFourRate: 0.75, WinRate: 0.1,
+			DamageDealt: 300, PlayersEliminated: 12, AverageBoardGold: 30,
+			Form: leaderboards.Form{LineAverage: 4},
+		},
+		"Di": {},
+	}
+
+	// Cy has no league entry.
+	leagues := map[string]tft.LeagueEntry{
+		"Ada": {Tier: tft.TierGold, Rank: tft.DivisionII, LeaguePoints: 50},
+		"Bo":  {Tier: tft.TierDiamond, Rank: tft.DivisionIV, LeaguePoints: 0},
+		"Di":  {Tier: tft.TierChallenger, Rank: tft.DivisionI, LeaguePoints: 900},
+	}
+
+	tests := []struct {
+		name string
+		in   leaderboards.RankArgs
+		want []string // Name:Rank, in order
+	}{
+		{name: "default", want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "average finish ties on games", in: leaderboards.RankArgs{Metric: leaderboards.MetricAverageFinish}, want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "median finish", in: leaderboards.RankArgs{Metric: leaderboards.MetricMedianFinish}, want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "top four rate", in: leaderboards.RankArgs{Metric: leaderboards.MetricTopFourRate}, want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "top fours", in: leaderboards.RankArgs{Metric: leaderboards.MetricTopFours}, want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "win rate", in: leaderboards.RankArgs{Metric: leaderboards.MetricWinRate}, want: []string{"Bo:1", "Ada:2", "Cy:3", "Di:0"}},
+		{name: "wins tie on average finish", in: leaderboards.RankArgs{Metric: leaderboards.MetricWins}, want: []string{"Ada:1", "Bo:2", "Cy:3", "Di:0"}},
+		{name: "damage dealt", in: leaderboards.RankArgs{Metric: leaderboards.MetricDamageDealt}, want: []string{"Bo:1", "Ada:2", "Cy:3", "Di:0"}},
+		{name: "players eliminated", in: leaderboards.RankArgs{Metric: leaderboards.MetricPlayersEliminated}, want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "average board gold", in: leaderboards.RankArgs{Metric: leaderboards.MetricAverageBoardGold}, want: []string{"Bo:1", "Ada:2", "Cy:3", "Di:0"}},
+		{name: "games", in: leaderboards.RankArgs{Metric: leaderboards.MetricGames}, want: []string{"Cy:1", "Ada:2", "Bo:3", "Di:0"}},
+		{name: "form", in: leaderboards.RankArgs{Metric: leaderboards.MetricForm}, want: []string{"Ada:1", "Bo:2", "Cy:3", "Di:0"}},
+		{name: "streak", in: leaderboards.RankArgs{Metric: leaderboards.MetricStreak}, want: []string{"Ada:1", "Bo:2", "Cy:3", "Di:0"}},
+		{name: "lp without a league", in: leaderboards.RankArgs{Metric: leaderboards.MetricLP}, want: []string{"Bo:1", "Ada:2", "Cy:3", "Di:0"}},
+		{
+			name: "shared ranks",
+			in:   leaderboards.RankArgs{Metric: leaderboards.MetricWins, TieBreakers: []leaderboards.Metric{leaderboards.MetricWins}},
+			want: []string{"Ada:1", "Bo:1", "Cy:3", "Di:0"},
+		},
+		{
+			name: "tie breakers in order",
+			in: leaderboards.RankArgs{
+				Metric:      leaderboards.MetricAverageFinish,
+				TieBreakers: []leaderboards.Metric{leaderboards.MetricWins, leaderboards.MetricGames},
+			},
+			want: []string{"Ada:1", "Cy:2", "Bo:3", "Di:0"},
+		},
+		{name: "min games", in: leaderboards.RankArgs{MinGames: 9}, want: []string{"Cy:1", "Ada:2", "Bo:0", "Di:0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := leaderboards.Rank(stats, leagues, &tt.in)
+
+			var got []string
+			for _, e := range ranked {
+				got = append(got, fmt.Sprintf("%s:%d", e.Name, e.Rank))
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankLeagues(t *testing.T) {
+	stats := map[string]leaderboards.Stats{"Ada": {Games: 1}, "Cy": {Games: 1}}
+	leagues := map[string]tft.LeagueEntry{"Ada": {Tier: tft.TierGold, Rank: tft.DivisionII, LeaguePoints: 50}}
+
+	ranked := leaderboards.Rank(stats, leagues, &leaderboards.RankArgs{Metric: leaderboards.MetricLP})
+	if len(ranked) != 2 {
+		t.Fatalf("got %d entries, want 2", len(ranked))
+	}
+
+	if e := ranked[0]; e.Name != "Ada" || e.Tier != tft.TierGold || e.Division != tft.DivisionII || e.LeaguePoints != 50 {
+		t.Errorf("got %+v, want Ada in Gold II with 50 LP", e)
+	}
+
+	// Unranked members rank below everyone with a league.
+	if e := ranked[1]; e.Name != "Cy" || e.Rank != 2 || e.Tier != "" {
+		t.Errorf("got %+v, want Cy unranked in second", e)
+	}
+}
@@ -0,0 +1,309 @@
+package leaderboards
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Rating defaults. Ratings are Elo-like, so a 400 point gap means the higher
+// rated player is expected to beat the lower rated player 10 times to 1.
+const (
+	DefaultRating  = 1500
+	DefaultRatingK = 32
+)
+
+// RatingStore persists the rating history of Leaderboards.
+type RatingStore interface {
+	// GetRatingHistory returns a board's RatingChanges, oldest first.
+	GetRatingHistory(ctx context.Context, boardID string) ([]RatingChange, error)
+	// PutRatingChanges appends RatingChanges to a board's history.
+	PutRatingChanges(ctx context.Context, boardID string, changes []RatingChange) error
+}
+
+// RatingChange is a player's rating before and after a shared match.
+type RatingChange struct {
+	MatchID   string    `json:"matchId"`
+	PUUID     string    `json:"puuid"`
+	StartedAt time.Time `json:"startedAt"`
+	// Placement among the board's players in the match, starting at 1.
+	Placement int     `json:"placement"`
+	Before    float64 `json:"before"`
+	After     float64 `json:"after"`
+	Delta     float64 `json:"delta"`
+}
+
+// Rating is a player's current rating on a board.
+type Rating struct {
+	PUUID  string  `json:"-"`
+	Name   string  `json:"name"`
+	Rating float64 `json:"rating"`
+	// Games shared with other players on the board.
+	Games int `json:"games"`
+	// LastDelta is the change from the player's most recent rated match.
+	LastDelta float64 `json:"lastDelta"`
+}
+
+// BoardRatings are a Leaderboard's current ratings, highest first,
+// and the history they were calculated from.
+type BoardRatings struct {
+	Ratings []Rating       `json:"ratings"`
+	History []RatingChange `json:"history"`
+}
+
+// RatingEngine rates players by their relative placements in shared lobbies.
+// Each pair of players in a match is scored as an Elo game, and a player's
+// change is the mean of their pairwise changes scaled by K.
+type RatingEngine struct {
+	// Initial rating of new players. Defaults to DefaultRating.
+	Initial float64
+	// K scales rating changes. Defaults to DefaultRatingK.
+	K float64
+}
+
+// Rate matches, oldest first, starting from the ratings in history.
+// Only matches played by at least two players are rated. Matches already in
+// history are skipped. Matches retrieved after newer matches were rated, e.g.
+// because they failed to be retrieved before, are rated from current ratings.
+func (e *RatingEngine) Rate(history []RatingChange, results PUUIDResults) []RatingChange {
+	var (
+		current = make(map[string]float64) // Key = PUUID
+		rated   = make(map[string]bool)    // Key = Match ID
+	)
+
+	for _, c := range history {
+		current[c.PUUID] = c.After
+		rated[c.MatchID] = true
+	}
+
+	// Group results by match.
+	lobbies := make(map[string][]Result) // Key = Match ID
+	for _, rr := range results {
+		for _, r := range rr {
+			if rated[r.MatchID] {
+				continue
+			}
+
+			lobbies[r.MatchID] = append(lobbies[r.MatchID], r)
+		}
+	}
+
+	var matchIDs []string
+	for id, rr := range lobbies {
+		if len(rr) < 2 {
+			continue
+		}
+
+		matchIDs = append(matchIDs, id)
+	}
+
+	sort.Slice(matchIDs, func(i, j int) bool {
+		ti, tj := lobbies[matchIDs[i]][0].StartedAt, lobbies[matchIDs[j]][0].StartedAt
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+
+		return matchIDs[i] < matchIDs[j]
+	})
+
+	var changes []RatingChange
+	for _, id := range matchIDs {
+		cc := e.rateMatch(current, lobbies[id])
+		for _, c := range cc {
+			current[c.PUUID] = c.After
+		}
+
+		changes = append(changes, cc...)
+	}
+
+	return changes
+}
+
+// rateMatch scores every pair of players in a match simultaneously.
+func (e *RatingEngine) rateMatch(current map[string]float64, rr []Result) []RatingChange {
+	sort.Slice(rr, func(i, j int) bool {
+		if rr[i].Placement != rr[j].Placement {
+			return rr[i].Placement < rr[j].Placement
+		}
+
+		return rr[i].PUUID < rr[j].PUUID
+	})
+
+	before := make([]float64, len(rr))
+	for i, r := range rr {
+		before[i] = e.initial()
+		if rating, ok := current[r.PUUID]; ok {
+			before[i] = rating
+		}
+	}
+
+	changes := make([]RatingChange, len(rr))
+	for i, r := range rr {
+		var score float64
+		for j, o := range rr {
+			if i == j {
+				continue
+			}
+
+			// Double Up partners can share a placement.
+			actual := 0.5
+			switch {
+			case r.Placement < o.Placement:
+				actual = 1
+			case r.Placement > o.Placement:
+				actual = 0
+			}
+
+			expected := 1 / (1 + math.Pow(10, (before[j]-before[i])/400))
+			score += actual - expected
+		}
+
+		delta := e.k() * score / float64(len(rr)-1)
+		changes[i] = RatingChange{
+			MatchID:   r.MatchID,
+			PUUID:     r.PUUID,
+			StartedAt: r.StartedAt,
+			Placement: i + 1,
+			Before:    before[i],
+			After:     before[i] + delta,
+			Delta:     delta,
+		}
+	}
+
+	return changes
+}
+
+func (e *RatingEngine) initial() float64 {
+	if e.Initial == 0 {
+		return DefaultRating
+	}
+
+	return e.Initial
+}
+
+func (e *RatingEngine) k() float64 {
+	if e.K == 0 {
+		return DefaultRatingK
+	}
+
+	return e.K
+}
+
+// CurrentRatings of a board's members from its history, highest first.
+// Members without rated matches have the engine's initial rating.
+func (e *RatingEngine) CurrentRatings(board *Leaderboard, history []RatingChange) []Rating {
+	ratings := make(map[string]*Rating)
+	for _, smnr := range board.Summoners {
+		ratings[smnr.PUUID] = &Rating{
+			PUUID:  smnr.PUUID,
+			Name:   smnr.Name,
+			Rating: e.initial(),
+		}
+	}
+
+	for _, c := range history {
+		r, ok := ratings[c.PUUID]
+		if !ok {
+			// Former members keep their history but leave the ratings.
+			continue
+		}
+
+		r.Rating = c.After
+		r.LastDelta = c.Delta
+		r.Games++
+	}
+
+	var out []Rating
+	for _, r := range ratings {
+		out = append(out, *r)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Rating != out[j].Rating {
+			return out[i].Rating > out[j].Rating
+		}
+
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+// GetRatings returns a Leaderboard's current ratings without rating new matches.
+func (s *Server) GetRatings(ctx context.Context, id string) (*BoardRatings, error) {
+	board, history, err := s.ratingHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &RatingEngine{}
+
+	return &BoardRatings{
+		Ratings: engine.CurrentRatings(board, history),
+		History: history,
+	}, nil
+}
+
+// UpdateRatings rates a Leaderboard's new shared matches and returns its
+// current ratings. Each shared match is rated with every member who played
+// it, even those past their own GameLimit. Failures to retrieve some results
+// are reported by a *tft.BatchError; ratings are still updated from those
+// that were retrieved.
+func (s *Server) UpdateRatings(ctx context.Context, id string, in *GetResultsArgs) (*BoardRatings, error) {
+	board, history, err := s.ratingHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	args := *in
+	args.Lobbies = true
+
+	out, rerr := s.GetResultsFromLeaderboard(ctx, id, &args)
+	if rerr != nil && !IsPartial(rerr) {
+		return nil, rerr
+	}
+
+	results := make(PUUIDResults)
+	for _, rr := range out {
+		for _, r := range rr {
+			results[r.PUUID] = append(results[r.PUUID], r)
+		}
+	}
+
+	engine := &RatingEngine{}
+	changes := engine.Rate(history, results)
+	if len(changes) > 0 {
+		if err := s.Ratings.PutRatingChanges(ctx, id, changes); err != nil {
+			return nil, errors.Wrap(err, "put rating changes failed")
+		}
+
+		history = append(history, changes...)
+	}
+
+	return &BoardRatings{
+		Ratings: engine.CurrentRatings(board, history),
+		History: history,
+	}, rerr
+}
+
+// ratingHistory returns a Leaderboard and its rating history.
+func (s *Server) ratingHistory(ctx context.Context, id string) (*Leaderboard, []RatingChange, error) {
+	if s.Ratings == nil {
+		return nil, nil, errors.New("ratings are not configured")
+	}
+
+	board, err := s.Storage.GetLeaderboard(ctx, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get leaderboard failed")
+	}
+
+	history, err := s.Ratings.GetRatingHistory(ctx, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get rating history failed")
+	}
+
+	return board, history, nil
+}
@@ -0,0 +1,97 @@
+package leaderboards_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+func TestRate(t *testing.T) {
+	day := time.Date(2019, 11, 4, 12, 0, 0, 0, time.UTC)
+
+	result := func(id string, at time.Time, puuid string, placement int) leaderboards.Result {
+		return leaderboards.Result{
+			MatchID:     id,
+			StartedAt:   at,
+			Participant: tft.Participant{PUUID: puuid, Placement: placement},
+		}
+	}
+
+	rated := func(id string, at time.Time, puuid string, after float64) leaderboards.RatingChange {
+		return leaderboards.RatingChange{MatchID: id, PUUID: puuid, StartedAt: at, Before: after, After: after}
+	}
+
+	tests := []struct {
+		name    string
+		history []leaderboards.RatingChange
+		results leaderboards.PUUIDResults
+		want    []string // Match IDs rated, oldest first
+	}{
+		{
+			name: "shared matches",
+			results: leaderboards.PUUIDResults{
+				puuidOne: {result("a", day, puuidOne, 1), result("b", day.Add(time.Hour), puuidOne, 2), result("solo", day.Add(2*time.Hour), puuidOne, 3)},
+				puuidTwo: {result("b", day.Add(time.Hour), puuidTwo, 1), result("a", day, puuidTwo, 4)},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name:    "rated matches",
+			history: []leaderboards.RatingChange{rated("a", day, puuidOne, 1516), rated("a", day, puuidTwo, 1484)},
+			results: leaderboards.PUUIDResults{
+				puuidOne: {result("a", day, puuidOne, 1), result("b", day.Add(time.Hour), puuidOne, 2)},
+				puuidTwo: {result("a", day, puuidTwo, 4), result("b", day.Add(time.Hour), puuidTwo, 1)},
+			},
+			want: []string{"b"},
+		},
+		{
+			name:    "late matches",
+			history: []leaderboards.RatingChange{rated("b", day.Add(time.Hour), puuidOne, 1516), rated("b", day.Add(time.Hour), puuidTwo, 1484)},
+			results: leaderboards.PUUIDResults{
+				puuidOne: {result("a", day, puuidOne, 1), result("b", day.Add(time.Hour), puuidOne, 1)},
+				puuidTwo: {result("a", day, puuidTwo, 4), result("b", day.Add(time.Hour), puuidTwo, 2)},
+			},
+			want: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &leaderboards.RatingEngine{}
+			changes := e.Rate(tt.history, tt.results)
+
+			var got []string
+			for _, c := range changes {
+				if len(got) == 0 || got[len(got)-1] != c.MatchID {
+					got = append(got, c.MatchID)
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("rated %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("rated %v, want %v", got, tt.want)
+				}
+			}
+
+			// Ratings continue from history.
+			current := make(map[string]float64)
+			for _, c := range tt.history {
+				current[c.PUUID] = c.After
+			}
+
+			for _, c := range changes {
+				if want, ok := current[c.PUUID]; ok && c.Before != want {
+					t.Errorf("%s rated from %.1f, want %.1f", c.MatchID, c.Before, want)
+				}
+
+				current[c.PUUID] = c.After
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package leaderboards
 import (
 	"context"
 	"encoding/json"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/alee792/teamfit/internal/fanout"
 	"github.com/alee792/teamfit/pkg/tft"
 	"github.com/pkg/errors"
 )
@@ -13,15 +16,118 @@ import (
 type Result struct {
 	MatchID   string    `json:"match_id"`
 	StartedAt time.Time `json:"started_at"`
+	// Set and GameVersion of the match, e.g. for looking up static data.
+	Set         int    `json:"set,omitempty"`
+	GameVersion string `json:"game_version,omitempty"`
+	QueueID     int    `json:"queue_id,omitempty"`
 	tft.Participant
 }
 
+// resultMeta are the Result's fields outside of its Participant.
+type resultMeta struct {
+	MatchID     string    `json:"match_id"`
+	StartedAt   time.Time `json:"started_at"`
+	Set         int       `json:"set,omitempty"`
+	GameVersion string    `json:"game_version,omitempty"`
+	QueueID     int       `json:"queue_id,omitempty"`
+}
+
+// MarshalJSON flattens the Result's fields into its Participant,
+// whose own MarshalJSON would otherwise hide them.
+func (r Result) MarshalJSON() ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := remarshal(r.Participant, &fields); err != nil {
+		return nil, err
+	}
+
+	var meta map[string]json.RawMessage
+	if err := remarshal(resultMeta{
+		MatchID:     r.MatchID,
+		StartedAt:   r.StartedAt,
+		Set:         r.Set,
+		GameVersion: r.GameVersion,
+		QueueID:     r.QueueID,
+	}, &meta); err != nil {
+		return nil, err
+	}
+
+	for k, v := range meta {
+		fields[k] = v
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON reverses MarshalJSON.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var meta resultMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &r.Participant); err != nil {
+		return err
+	}
+
+	r.MatchID, r.StartedAt = meta.MatchID, meta.StartedAt
+	r.Set, r.GameVersion, r.QueueID = meta.Set, meta.GameVersion, meta.QueueID
+
+	for _, k := range []string{"match_id", "started_at", "set", "game_version", "queue_id"} {
+		delete(r.Participant.Extra, k)
+	}
+
+	if len(r.Participant.Extra) == 0 {
+		r.Participant.Extra = nil
+	}
+
+	return nil
+}
+
+// remarshal v into a map of its JSON fields.
+func remarshal(v interface{}, fields *map[string]json.RawMessage) error {
+	bb, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bb, fields)
+}
+
 // GetResultsArgs allows users to query match results.
 // Must be paired with additional identifiers, either a Leaderboard or list of Summoners.
 type GetResultsArgs struct {
+	// GameLimit per Summoner. Below 1 it's a single match, or with a Period
+	// every match in the Period.
 	GameLimit int
 	Before    time.Time
 	After     time.Time
+	// Platforms overrides the API's Platform for individual Summoners.
+	Platforms map[string]tft.Platform // Key = Summoner.PUUID
+	// Queues to include, e.g. tft.QueueRanked. Empty includes every queue.
+	// Histories are paged until GameLimit matches in the Queues are found.
+	Queues []int
+	// Period keeps matches from the patch or set of the newest match
+	// played by any of the Summoners. Histories are paged until the Period
+	// ends. Empty keeps every match.
+	Period Period
+	// Lobbies includes every Summoner's result from each retrieved match,
+	// even past their own GameLimit, so shared matches are complete.
+	Lobbies bool
+}
+
+// includesQueue reports whether a queue is one of the args' Queues.
+func (in *GetResultsArgs) includesQueue(queueID int) bool {
+	if len(in.Queues) == 0 {
+		return true
+	}
+
+	for _, q := range in.Queues {
+		if q == queueID {
+			return true
+		}
+	}
+
+	return false
 }
 
 // PUUIDResults returns Summoner's match results with PUUIDs as keys.
@@ -32,11 +138,50 @@ type NameResults map[string][]Result
 
 type Summoner struct {
 	tft.Summoner
-	tft.LeagueEntry
+	// Leagues the Summoner is ranked in, e.g. RANKED_TFT and RANKED_TFT_DOUBLE_UP.
+	Leagues map[string]tft.LeagueEntry // Key = LeagueEntry.QueueType
+	// Platform the Summoner plays on. Empty defers to the API's Platform.
+	Platform tft.Platform
+	// Awards earned on Leaderboards. Not persisted with Leaderboards.
+	Awards []Award `json:"awards,omitempty"`
+}
+
+// leagueJSON is a LeagueEntry without confidential fields.
+type leagueJSON struct {
+	Inactive       bool   `json:"inactive"`
+	FreshBlood     bool   `json:"freshBlood"`
+	Veteran        bool   `json:"veteran"`
+	HotStreak      bool   `json:"hotStreak"`
+	QueueType      string `json:"queueType"`
+	Wins           int    `json:"wins"`
+	Losses         int    `json:"losses"`
+	Rank           string `json:"rank"`
+	LeagueID       string `json:"leagueId"`
+	Tier           string `json:"tier"`
+	LeaguePoints   int    `json:"leaguePoints"`
+	tft.MiniSeries `json:"miniSeries,omitempty"`
 }
 
 // MarshalJSON hides confidential fields.
 func (s *Summoner) MarshalJSON() ([]byte, error) {
+	leagues := make(map[string]leagueJSON)
+	for queue, le := range s.Leagues {
+		leagues[queue] = leagueJSON{
+			Inactive:     le.Inactive,
+			FreshBlood:   le.FreshBlood,
+			Veteran:      le.Veteran,
+			HotStreak:    le.HotStreak,
+			QueueType:    le.QueueType,
+			Wins:         le.Wins,
+			Losses:       le.Losses,
+			Rank:         le.Rank,
+			LeagueID:     le.LeagueID,
+			Tier:         le.Tier,
+			LeaguePoints: le.LeaguePoints,
+			MiniSeries:   le.MiniSeries,
+		}
+	}
+
 	return json.Marshal(&struct {
 		// Summoner
 		ProileIconID  int    `json:"-"`
@@ -46,117 +191,349 @@ func (s *Summoner) MarshalJSON() ([]byte, error) {
 		AccountID     string `json:"-"`
 		ID            string `json:"-"`
 		RevisionDate  int    `json:"revisionDate"`
-		// LeagueEntry
-		Inactive       bool   `json:"inactive"`
-		FreshBlood     bool   `json:"freshBlood"`
-		Veteran        bool   `json:"veteran"`
-		HotStreak      bool   `json:"hotStreak"`
-		QueueType      string `json:"queueType"`
-		SummonerName   string `json:"summonerName,omitempty"`
-		Wins           int    `json:"wins"`
-		Losses         int    `json:"losses"`
-		Rank           string `json:"rank"`
-		LeagueID       string `json:"leagueId"`
-		Tier           string `json:"tier"`
-		LeaguePoints   int    `json:"leaguePoints"`
-		tft.MiniSeries `json:"miniSeries,omitempty"`
+		// LeagueEntries
+		Leagues  map[string]leagueJSON `json:"leagues"`
+		Platform tft.Platform          `json:"platform,omitempty"`
+		Awards   []Award               `json:"awards,omitempty"`
 	}{
 		Name:          s.Name,
 		SummonerLevel: s.SummonerLevel,
 		RevisionDate:  s.RevisionDate,
-		Inactive:      s.Inactive,
-		FreshBlood:    s.FreshBlood,
-		Veteran:       s.Veteran,
-		HotStreak:     s.HotStreak,
-		QueueType:     s.QueueType,
-		Wins:          s.Wins,
-		Losses:        s.Losses,
-		Rank:          s.Rank,
-		LeagueID:      s.LeagueID,
-		Tier:          s.Tier,
-		LeaguePoints:  s.LeaguePoints,
-		MiniSeries:    s.MiniSeries,
+		Leagues:       leagues,
+		Platform:      s.Platform,
+		Awards:        s.Awards,
 	})
 }
 
 // GetResults for a set of Summoners.
+// Each Summoner's history is paged, newest first, until GameLimit matches
+// within the args' time range and Queues are retrieved or the history runs
+// out. Matches are retrieved concurrently, and only once however many of the
+// Summoners played them. Summoners and matches that could not be retrieved are
+// reported by a *tft.BatchError alongside the results that could.
 func (s *Server) GetResults(ctx context.Context, puuids []string, in *GetResultsArgs) (PUUIDResults, error) {
-	if in.GameLimit < 1 {
+	if in.GameLimit < 1 && in.Period == "" {
 		in.GameLimit = 1
 	}
 
-	// Collate match IDs to query.
 	var (
-		matches  = make(map[string]*tft.Match)
-		matchIDs []string
+		failures  = make(map[string]error)
+		cache     = newMatchCache(s.API, s.parallelism())
+		collected = make([][]matchWithID, len(puuids))
+		matchErrs = make([]map[string]error, len(puuids))
+		keep      = in.filter
 	)
 
-	for _, id := range puuids {
-		out, err := s.API.ListMatches(ctx, &tft.ListMatchesRequest{
-			PUUID: id,
-		})
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to list matches for %s", id)
+	// Periods are relative to the newest match, so it's found first.
+	// Failures are reported when the histories are paged again below.
+	if in.Period != "" {
+		newest := s.newestMatch(ctx, cache, puuids, in)
+		if newest == nil {
+			return emptyResults(puuids), nil
+		}
+
+		keep = in.periodFilter(newest)
+	}
+
+	errs := fanout.Do(ctx, len(puuids), s.parallelism(), func(ctx context.Context, i int) error {
+		var err error
+		collected[i], matchErrs[i], err = s.collectMatches(ctx, cache, puuids[i], in, keep)
+		return err
+	})
+
+	var (
+		seen      = make(map[string]bool)
+		retrieved []matchWithID
+	)
+
+	for i := range puuids {
+		if errs[i] != nil {
+			failures[puuids[i]] = errors.Wrap(errs[i], "failed to list matches")
 		}
 
-		matchIDs = out.MatchIDs
-		for _, id := range matchIDs {
-			matches[id] = nil
+		for id, err := range matchErrs[i] {
+			failures[id] = errors.Wrap(err, "unable to retrieve match")
+		}
+
+		for _, m := range collected[i] {
+			// Tracked players often share matches.
+			if seen[m.ID] {
+				continue
+			}
+
+			seen[m.ID] = true
+			retrieved = append(retrieved, m)
 		}
 	}
 
+	// Collate newest first so match limits keep the most recent games.
+	sort.SliceStable(retrieved, func(i, j int) bool {
+		ti, tj := retrieved[i].Match.Info.GameTimestamp, retrieved[j].Match.Info.GameTimestamp
+		if ti != tj {
+			return ti > tj
+		}
+
+		return retrieved[i].ID < retrieved[j].ID
+	})
+
 	// Prepare results and Leaderboard.
 	// Participants are really match results for participants.
+	results := emptyResults(puuids)
+
+	for _, m := range retrieved {
+		// Append match results if player is tracked on Leaderboard.
+		for _, p := range m.Match.Info.Participants {
+			_, ok := results[p.PUUID]
+			if !ok {
+				continue
+			}
+
+			// Do not append results if a player exceeds the match limit.
+			if !in.Lobbies && in.GameLimit > 0 && len(results[p.PUUID]) >= in.GameLimit {
+				continue
+			}
+
+			results[p.PUUID] = append(results[p.PUUID], Result{
+				MatchID:     m.ID,
+				StartedAt:   UnixMS(m.Match.Info.GameTimestamp),
+				Set:         m.Match.Info.Set,
+				GameVersion: m.Match.Info.GameVersion,
+				QueueID:     m.Match.Info.QueueID,
+				Participant: s.Static.Enrich(&m.Match.Info, p),
+			})
+		}
+	}
+
+	return results, batchError(failures)
+}
+
+// emptyResults for each of puuids.
+func emptyResults(puuids []string) PUUIDResults {
 	var results PUUIDResults = make(map[string][]Result) // Key = Summoner.PUUID
 	for _, id := range puuids {
 		results[id] = []Result{}
 	}
 
-	// Retrieve matches and collate results.
-	for _, id := range matchIDs {
-		out, err := s.API.GetMatch(ctx, &tft.GetMatchRequest{
-			MatchID: id,
-		})
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to retrieve match %s", id)
+	return results
+}
+
+// newestMatch within the args' time range and Queues played by any of puuids.
+func (s *Server) newestMatch(ctx context.Context, cache *matchCache, puuids []string, in *GetResultsArgs) *tft.Match {
+	first := *in
+	first.GameLimit = 1
+
+	newest := make([]*tft.Match, len(puuids))
+	fanout.Do(ctx, len(puuids), s.parallelism(), func(ctx context.Context, i int) error {
+		kept, _, err := s.collectMatches(ctx, cache, puuids[i], &first, first.filter)
+		if len(kept) > 0 {
+			newest[i] = kept[0].Match
 		}
 
-		// Enforce time range.
-		gameStart := UnixMS(out.Match.Info.GameTimestamp)
-		if (!in.Before.IsZero() && gameStart.After(in.Before)) || (!in.After.IsZero() && gameStart.Before(in.After)) {
-			continue
+		return err
+	})
+
+	var out *tft.Match
+	for _, m := range newest {
+		if m != nil && (out == nil || m.Info.GameTimestamp > out.Info.GameTimestamp) {
+			out = m
 		}
+	}
 
-		matches[id] = &out.Match
+	return out
+}
 
-		// Append match results if player is tracked on Leaderboard.
-		for _, p := range out.Match.Info.Participants {
-			_, ok := results[p.PUUID]
-			if !ok {
+// collectMatches pages through a Summoner's history, newest first, until
+// GameLimit matches that keep accepts are retrieved, keep rules out older
+// matches or the history runs out. Without a GameLimit, only the latter two
+// stop paging. Matches that could not be retrieved are returned by ID and not
+// counted.
+func (s *Server) collectMatches(ctx context.Context, cache *matchCache, puuid string, in *GetResultsArgs, keep matchFilter) ([]matchWithID, map[string]error, error) {
+	if p, ok := in.Platforms[puuid]; ok {
+		ctx = tft.WithPlatform(ctx, p)
+	}
+
+	// Time ranges are pushed down to the API so only relevant matches are listed.
+	it := tft.NewMatchIterator(s.API, tft.ListMatchesRequest{
+		PUUID:     puuid,
+		Count:     in.pageSize(),
+		StartTime: in.After,
+		EndTime:   in.Before,
+	}, 0)
+
+	var (
+		kept     []matchWithID
+		failures = make(map[string]error)
+		more     = true
+	)
+
+	for more && (in.GameLimit < 1 || len(kept) < in.GameLimit) {
+		// Retrieve as many matches as are still needed at once.
+		need := in.pageSize()
+		if in.GameLimit > 0 {
+			need = in.GameLimit - len(kept)
+		}
+
+		var ids []string
+		for len(ids) < need && it.Next(ctx) {
+			ids = append(ids, it.MatchID())
+		}
+
+		if len(ids) == 0 {
+			break
+		}
+
+		matches := make([]*tft.Match, len(ids))
+		errs := fanout.Do(ctx, len(ids), s.parallelism(), func(ctx context.Context, i int) error {
+			var err error
+			matches[i], err = cache.get(ctx, ids[i])
+			return err
+		})
+
+		for i, m := range matches {
+			if errs[i] != nil {
+				failures[ids[i]] = errs[i]
 				continue
 			}
 
-			// Do not append results if a player exceeds the match limit.
-			// The assumption is that listed match IDs are returned in chronological order.
-			if len(results[p.PUUID]) >= in.GameLimit {
+			var ok bool
+			if ok, more = keep(m); !more {
 				break
 			}
 
-			results[p.PUUID] = append(results[p.PUUID], Result{
-				MatchID:     id,
-				StartedAt:   gameStart,
-				Participant: p,
-			})
+			if ok {
+				kept = append(kept, matchWithID{ID: ids[i], Match: m})
+			}
+		}
+	}
+
+	return kept, failures, it.Err()
+}
+
+// matchFilter reports whether to keep a match from a history, and whether
+// older matches may still be kept.
+type matchFilter func(m *tft.Match) (keep, more bool)
+
+// filter keeps matches within the args' time range and Queues.
+func (in *GetResultsArgs) filter(m *tft.Match) (keep, more bool) {
+	return in.keeps(m), true
+}
+
+// periodFilter keeps matches within the args' time range, Queues and the
+// Period of newest. Patches and sets don't recur, so once a history reaches
+// an earlier one the Period is over. Revivals replay earlier sets and don't.
+func (in *GetResultsArgs) periodFilter(newest *tft.Match) matchFilter {
+	period := periodOf(in.Period, newest)
+
+	return func(m *tft.Match) (keep, more bool) {
+		switch {
+		case m.Info.QueueID == tft.QueueRevival:
+		case in.Period == PeriodPatch && tft.ComparePatches(m.Info.Patch(), newest.Info.Patch()) < 0:
+			return false, false
+		case m.Info.Set < newest.Info.Set:
+			return false, false
+		}
+
+		return in.keeps(m) && periodOf(in.Period, m) == period, true
+	}
+}
+
+// keeps reports whether a match is within the args' time range and Queues.
+func (in *GetResultsArgs) keeps(m *tft.Match) bool {
+	gameStart := UnixMS(m.Info.GameTimestamp)
+	if (!in.Before.IsZero() && gameStart.After(in.Before)) || (!in.After.IsZero() && gameStart.Before(in.After)) {
+		return false
+	}
+
+	return in.includesQueue(m.Info.QueueID)
+}
+
+// pageSize of match listings. Unless matches are filtered after listing,
+// only GameLimit match IDs are needed.
+func (in *GetResultsArgs) pageSize() int {
+	if len(in.Queues) == 0 && in.Period == "" && in.GameLimit < tft.DefaultPageSize {
+		return in.GameLimit
+	}
+
+	return tft.DefaultPageSize
+}
+
+// matchCache retrieves each match once, however many Summoners list it,
+// with at most parallelism retrievals in flight.
+type matchCache struct {
+	api   API
+	sem   chan struct{}
+	mux   sync.Mutex
+	calls map[string]*matchCall // Key = Match ID
+}
+
+// matchCall is a match retrieval that's done once done is closed.
+type matchCall struct {
+	done  chan struct{}
+	match *tft.Match
+	err   error
+}
+
+func newMatchCache(api API, parallelism int) *matchCache {
+	return &matchCache{
+		api:   api,
+		sem:   make(chan struct{}, parallelism),
+		calls: make(map[string]*matchCall),
+	}
+}
+
+func (c *matchCache) get(ctx context.Context, id string) (*tft.Match, error) {
+	c.mux.Lock()
+	call, ok := c.calls[id]
+	if !ok {
+		call = &matchCall{done: make(chan struct{})}
+		c.calls[id] = call
+	}
+	c.mux.Unlock()
+
+	if ok {
+		select {
+		case <-call.done:
+			return call.match, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
 
-	return results, nil
+	defer close(call.done)
+
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		call.err = ctx.Err()
+		return nil, call.err
+	}
+	defer func() { <-c.sem }()
+
+	out, err := c.api.GetMatch(ctx, &tft.GetMatchRequest{
+		MatchID: id,
+	})
+	if err != nil {
+		call.err = err
+		return nil, err
+	}
+
+	call.match = &out.Match
+
+	return call.match, nil
+}
+
+// matchWithID pairs a Match with the ID it was listed under.
+type matchWithID struct {
+	ID    string
+	Match *tft.Match
 }
 
 // GetResultsFromNames retrives results of Summoners attached to a Leaderboard.
 func (s *Server) GetResultsFromNames(ctx context.Context, names []string, in *GetResultsArgs) (NameResults, error) {
+	failures := make(map[string]error)
+
 	smnrs, err := s.API.GetSummoners(ctx, names)
-	if err != nil {
+	if err := mergeFailures(failures, err); err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve summoner PUUIDs")
 	}
 
@@ -172,7 +549,7 @@ func (s *Server) GetResultsFromNames(ctx context.Context, names []string, in *Ge
 	}
 
 	out, err := s.GetResults(ctx, ids, in)
-	if err != nil {
+	if err := mergeFailures(failures, err); err != nil {
 		return nil, err
 	}
 
@@ -180,41 +557,102 @@ func (s *Server) GetResultsFromNames(ctx context.Context, names []string, in *Ge
 		nameResults[smnr.Name] = out[smnr.PUUID]
 	}
 
-	return nameResults, nil
+	return nameResults, batchError(failures)
 }
 
 // GetResultsFromLeaderboard retrieves results of Summoners attached to a Leaderboard.
-func (s *Server) GetResultsFromLeaderboard(ctx context.Context, puuid string, in *GetResultsArgs) (NameResults, error) {
-	board, err := s.Storage.GetLeaderboard(ctx, puuid)
+// Each Summoner is queried on the Platform they were added from.
+func (s *Server) GetResultsFromLeaderboard(ctx context.Context, id string, in *GetResultsArgs) (NameResults, error) {
+	board, err := s.Storage.GetLeaderboard(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "get leaderboard failed")
 	}
 
-	var names []string
+	var (
+		args      = *in
+		platforms = make(map[string]tft.Platform)
+		puuids    []string
+	)
+
 	for _, smnr := range board.Summoners {
-		names = append(names, smnr.Name)
+		puuids = append(puuids, smnr.PUUID)
+		if smnr.Platform != "" {
+			platforms[smnr.PUUID] = smnr.Platform
+		}
 	}
 
-	return s.GetResultsFromNames(ctx, names, in)
+	args.Platforms = platforms
+
+	// Boards may restrict their queues, e.g. to ranked only.
+	if len(args.Queues) == 0 {
+		args.Queues = board.Queues
+	}
+
+	out, err := s.GetResults(ctx, puuids, &args)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	var nameResults NameResults = make(map[string][]Result)
+	for _, smnr := range board.Summoners {
+		nameResults[smnr.Name] = out[smnr.PUUID]
+	}
+
+	return nameResults, err
 }
 
 func (s *Server) GetSummoner(ctx context.Context, summonerName string) (*Summoner, error) {
+	// Riot IDs are resolved through their account.
+	if gameName, tagLine, ok := tft.ParseRiotID(summonerName); ok {
+		return s.GetSummonerByRiotID(ctx, gameName, tagLine)
+	}
+
 	smnr, err := s.API.GetSummoner(ctx, summonerName)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get summoner")
 	}
 
-	le, err := s.API.GetLeagueEntry(ctx, smnr.ID)
+	return s.withLeagues(ctx, smnr)
+}
+
+// GetSummonerByPUUID, which is stable across name changes.
+func (s *Server) GetSummonerByPUUID(ctx context.Context, puuid string) (*Summoner, error) {
+	smnr, err := s.API.GetSummonerByPUUID(ctx, puuid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get summoner")
+	}
+
+	return s.withLeagues(ctx, smnr)
+}
+
+// GetSummonerByRiotID, i.e. gameName#tagLine.
+func (s *Server) GetSummonerByRiotID(ctx context.Context, gameName, tagLine string) (*Summoner, error) {
+	acct, err := s.API.GetAccountByRiotID(ctx, gameName, tagLine)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get league entry")
+		return nil, errors.Wrap(err, "failed to get account")
 	}
 
-	if le == nil {
-		le = &tft.LeagueEntry{}
+	return s.GetSummonerByPUUID(ctx, acct.PUUID)
+}
+
+func (s *Server) withLeagues(ctx context.Context, smnr *tft.Summoner) (*Summoner, error) {
+	entries, err := s.API.GetLeagueEntries(ctx, smnr.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get league entries")
 	}
 
+	leagues := make(map[string]tft.LeagueEntry)
+	for _, le := range entries {
+		leagues[le.QueueType] = le
+	}
+
+	// Remember where the Summoner was found so they can be
+	// tracked alongside Summoners from other Platforms.
+	platform, _ := tft.PlatformFromContext(ctx)
+
 	return &Summoner{
-		Summoner:    *smnr,
-		LeagueEntry: *le,
+		Summoner: *smnr,
+		Leagues:  leagues,
+		Platform: platform,
 	}, nil
 }
@@ -0,0 +1,324 @@
+package leaderboards_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/alee792/teamfit/pkg/tft/tfttest"
+)
+
+// Fixture players, who share the fixture match.
+const (
+	fixtureMatch = "NA1_3206016526"
+	puuidOne     = "54P482v2PkgW1Zg41rv7t7dSS2W_Fxj-2GrDH5gs8fHLgMMxSirgmyijTMfw0vnxYKu3IFIgZ1dMSg"
+	puuidTwo     = "9vUosIFFOpDht0Wuyetw3XZ17ajqyGhHTfctj8bfdL72hLyTPOwZHnBDQMAk7Goh1ZA3Nxs6AUFVyA"
+)
+
+// newTestServer serves fixtures from a fake Riot API. Close it when finished.
+func newTestServer(t *testing.T) (*leaderboards.Server, *tfttest.Server) {
+	t.Helper()
+
+	f, err := tfttest.LoadFixtures("../tft/tfttest/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := tfttest.NewServer(f)
+
+	return &leaderboards.Server{API: srv.APIClient()}, srv
+}
+
+// withHistory replaces the fixture matches with copies of the fixture match,
+// newest first, played in queues. Rate limits are lifted for long histories.
+func withHistory(t *testing.T, s *leaderboards.Server, srv *tfttest.Server, queues ...int) {
+	t.Helper()
+
+	if len(srv.Fixtures.Matches) == 0 {
+		t.Fatal("no fixture match")
+	}
+
+	var (
+		m       = srv.Fixtures.Matches[0]
+		matches []tft.Match
+	)
+
+	for i, queue := range queues {
+		cp := m
+		cp.Metadata.MatchID = fmt.Sprintf("NA1_%d", 1000+len(queues)-i)
+		cp.Info.QueueID = queue
+		cp.Info.GameTimestamp = m.Info.GameTimestamp - i*3600*1000
+		matches = append(matches, cp)
+	}
+
+	srv.Fixtures.Matches = matches
+	srv.AppRateLimit = ""
+
+	cfg := srv.Config()
+	cfg.AppRateLimit = "1000:1"
+	s.API = tft.NewClient(srv.Client(), cfg)
+}
+
+func TestGetResults(t *testing.T) {
+	s, srv := newTestServer(t)
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		in   leaderboards.GetResultsArgs
+		want map[string][]int // Key = PUUID, placements newest first
+	}{
+		{
+			name: "recent games",
+			in:   leaderboards.GetResultsArgs{GameLimit: 5},
+			want: map[string][]int{puuidOne: {6}, puuidTwo: {1}},
+		},
+		{
+			name: "matching queue",
+			in:   leaderboards.GetResultsArgs{GameLimit: 5, Queues: []int{tft.QueueNormal}},
+			want: map[string][]int{puuidOne: {6}, puuidTwo: {1}},
+		},
+		{
+			name: "other queue",
+			in:   leaderboards.GetResultsArgs{GameLimit: 5, Queues: []int{tft.QueueRanked}},
+			want: map[string][]int{puuidOne: {}, puuidTwo: {}},
+		},
+		{
+			name: "after the match",
+			in:   leaderboards.GetResultsArgs{GameLimit: 5, After: time.Date(2019, 11, 15, 0, 0, 0, 0, time.UTC)},
+			want: map[string][]int{puuidOne: {}, puuidTwo: {}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := s.GetResults(context.Background(), []string{puuidOne, puuidTwo}, &tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for puuid, want := range tt.want {
+				rr := out[puuid]
+				if len(rr) != len(want) {
+					t.Fatalf("%s has %d results, want %d", puuid, len(rr), len(want))
+				}
+
+				for i, r := range rr {
+					if r.MatchID != fixtureMatch || r.PUUID != puuid || r.Placement != want[i] {
+						t.Errorf("result %d = %s %s placed %d, want %s %s placed %d",
+							i, r.MatchID, r.PUUID, r.Placement, fixtureMatch, puuid, want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetResultsPagesQueues(t *testing.T) {
+	s, srv := newTestServer(t)
+	defer srv.Close()
+
+	// Ranked games are past the first page of normal games.
+	var queues []int
+	for i := 0; i < 25; i++ {
+		queues = append(queues, tft.QueueNormal)
+	}
+
+	queues = append(queues, tft.QueueRanked, tft.QueueNormal, tft.QueueRanked, tft.QueueRanked)
+	withHistory(t, s, srv, queues...)
+
+	out, err := s.GetResults(context.Background(), []string{puuidOne}, &leaderboards.GetResultsArgs{
+		GameLimit: 2,
+		Queues:    []int{tft.QueueRanked},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"NA1_1004", "NA1_1002"}
+	if rr := out[puuidOne]; len(rr) != len(want) {
+		t.Fatalf("got %d results, want %d", len(rr), len(want))
+	}
+
+	for i, r := range out[puuidOne] {
+		if r.MatchID != want[i] || r.QueueID != tft.QueueRanked {
+			t.Errorf("result %d = %s in queue %d, want %s in queue %d", i, r.MatchID, r.QueueID, want[i], tft.QueueRanked)
+		}
+	}
+}
+
+func TestGetResultsPeriod(t *testing.T) {
+	// Newest first: 3 games on 10.1 and 3 on 9.24 in set 3, then a long set 2 history.
+	versions := []struct {
+		set   int
+		patch string
+		games int
+	}{
+		{set: 3, patch: "10.1", games: 3},
+		{set: 3, patch: "9.24", games: 3},
+		{set: 2, patch: "9.22", games: 50},
+	}
+
+	tests := []struct {
+		name  string
+		in    leaderboards.GetResultsArgs
+		games int
+	}{
+		{name: "patch", in: leaderboards.GetResultsArgs{Period: leaderboards.PeriodPatch}, games: 3},
+		{name: "season", in: leaderboards.GetResultsArgs{Period: leaderboards.PeriodSeason}, games: 6},
+		{name: "capped", in: leaderboards.GetResultsArgs{Period: leaderboards.PeriodSeason, GameLimit: 4}, games: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, srv := newTestServer(t)
+			defer srv.Close()
+
+			var queues []int
+			for _, v := range versions {
+				for i := 0; i < v.games; i++ {
+					queues = append(queues, tft.QueueRanked)
+				}
+			}
+
+			withHistory(t, s, srv, queues...)
+
+			i := 0
+			for _, v := range versions {
+				for j := 0; j < v.games; j++ {
+					srv.Fixtures.Matches[i].Info.Set = v.set
+					srv.Fixtures.Matches[i].Info.GameVersion = "Version " + v.patch + ".1.1"
+					i++
+				}
+			}
+
+			out, err := s.GetResults(context.Background(), []string{puuidOne}, &tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := len(out[puuidOne]); got != tt.games {
+				t.Errorf("got %d results, want %d", got, tt.games)
+			}
+
+			// Paging stops at the end of the period rather than the history.
+			if n := srv.Requests(); n > 2*tft.DefaultPageSize {
+				t.Errorf("served %d requests, want at most %d", n, 2*tft.DefaultPageSize)
+			}
+		})
+	}
+}
+
+func TestGetResultsLobbies(t *testing.T) {
+	tests := []struct {
+		name    string
+		lobbies bool
+		want    map[string][]string // Key = PUUID, match IDs newest first
+	}{
+		{
+			name: "game limits",
+			want: map[string][]string{puuidOne: {"NA1_1001"}, puuidTwo: {"NA1_1002"}},
+		},
+		{
+			name:    "whole lobbies",
+			lobbies: true,
+			want:    map[string][]string{puuidOne: {"NA1_1001"}, puuidTwo: {"NA1_1002", "NA1_1001"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, srv := newTestServer(t)
+			defer srv.Close()
+
+			withHistory(t, s, srv, tft.QueueRanked, tft.QueueRanked)
+
+			// The newest match is only shared with strangers by the second player.
+			m := &srv.Fixtures.Matches[0]
+			m.Metadata.Participants = append([]string(nil), m.Metadata.Participants...)
+			m.Info.Participants = append([]tft.Participant(nil), m.Info.Participants...)
+			for i := range m.Metadata.Participants {
+				if m.Metadata.Participants[i] == puuidOne {
+					m.Metadata.Participants[i] = "stranger"
+				}
+			}
+
+			for i := range m.Info.Participants {
+				if m.Info.Participants[i].PUUID == puuidOne {
+					m.Info.Participants[i].PUUID = "stranger"
+				}
+			}
+
+			out, err := s.GetResults(context.Background(), []string{puuidOne, puuidTwo}, &leaderboards.GetResultsArgs{
+				GameLimit: 1,
+				Lobbies:   tt.lobbies,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for puuid, want := range tt.want {
+				var got []string
+				for _, r := range out[puuid] {
+					got = append(got, r.MatchID)
+				}
+
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("%.8s played %v, want %v", puuid, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	s, srv := newTestServer(t)
+	defer srv.Close()
+
+	out, err := s.GetStats(context.Background(), []string{"Tactician One", "Tactician Two", "Nobody"}, &leaderboards.GetStatsArgs{
+		GameLimit: 5,
+	})
+
+	// Unknown summoners are reported alongside the others' stats.
+	var batch *tft.BatchError
+	if !errors.As(err, &batch) {
+		t.Fatalf("err = %v, want a *tft.BatchError", err)
+	}
+
+	if _, ok := batch.Errors["Nobody"]; !ok || len(batch.Errors) != 1 {
+		t.Errorf("failures = %v, want Nobody", batch.Errors)
+	}
+
+	tests := []struct {
+		name string
+		want leaderboards.Stats
+	}{
+		{
+			name: "Tactician One",
+			want: leaderboards.Stats{Games: 1, Wins: 0, TopFours: 0, AverageFinish: 6, DamageDealt: 86},
+		},
+		{
+			name: "Tactician Two",
+			want: leaderboards.Stats{Games: 1, Wins: 1, TopFours: 1, AverageFinish: 1, DamageDealt: 187, PlayersEliminated: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := out[tt.name]
+		if !ok {
+			t.Errorf("missing stats for %s", tt.name)
+			continue
+		}
+
+		if got.Games != tt.want.Games || got.Wins != tt.want.Wins || got.TopFours != tt.want.TopFours ||
+			got.AverageFinish != tt.want.AverageFinish || got.DamageDealt != tt.want.DamageDealt ||
+			got.PlayersEliminated != tt.want.PlayersEliminated {
+			t.Errorf("%s stats = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
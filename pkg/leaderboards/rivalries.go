@@ -0,0 +1,100 @@
+package leaderboards
+
+import (
+	"context"
+	"time"
+)
+
+// HeadToHead is a player's record against an opponent in shared matches.
+type HeadToHead struct {
+	// Wins are meetings the player finished ahead of the opponent.
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	// Ties are Double Up meetings as partners.
+	Ties     int `json:"ties"`
+	Meetings int `json:"meetings"`
+	// AveragePlacementGap is the opponent's placement minus the player's,
+	// so a positive gap means the player usually finishes ahead.
+	AveragePlacementGap float32   `json:"averagePlacementGap"`
+	LastMeeting         time.Time `json:"lastMeeting"`
+	LastMatchID         string    `json:"lastMatchId"`
+}
+
+// Rivalries are the head-to-head records between every pair of players
+// who have shared a match.
+type Rivalries map[string]map[string]HeadToHead // Key = Summoner.Name, then opponent's Summoner.Name
+
+// GetRivalries of a Leaderboard's members from their recent results.
+func (s *Server) GetRivalries(ctx context.Context, id string, in *GetResultsArgs) (Rivalries, error) {
+	out, err := s.GetResultsFromLeaderboard(ctx, id, in)
+	if err != nil && !IsPartial(err) {
+		return nil, err
+	}
+
+	return CalculateRivalries(out), err
+}
+
+// CalculateRivalries from results by name.
+func CalculateRivalries(results NameResults) Rivalries {
+	type placement struct {
+		name string
+		Result
+	}
+
+	// Group results by match.
+	lobbies := make(map[string][]placement) // Key = Match ID
+	for name, rr := range results {
+		for _, r := range rr {
+			lobbies[r.MatchID] = append(lobbies[r.MatchID], placement{name: name, Result: r})
+		}
+	}
+
+	var (
+		rivalries Rivalries = make(map[string]map[string]HeadToHead)
+		gaps                = make(map[string]map[string]int)
+	)
+
+	for _, pp := range lobbies {
+		for _, p := range pp {
+			for _, o := range pp {
+				if p.name == o.name {
+					continue
+				}
+
+				if rivalries[p.name] == nil {
+					rivalries[p.name] = make(map[string]HeadToHead)
+					gaps[p.name] = make(map[string]int)
+				}
+
+				h2h := rivalries[p.name][o.name]
+				h2h.Meetings++
+
+				switch {
+				case p.Placement < o.Placement:
+					h2h.Wins++
+				case p.Placement > o.Placement:
+					h2h.Losses++
+				default:
+					h2h.Ties++
+				}
+
+				if p.StartedAt.After(h2h.LastMeeting) {
+					h2h.LastMeeting = p.StartedAt
+					h2h.LastMatchID = p.MatchID
+				}
+
+				gaps[p.name][o.name] += o.Placement - p.Placement
+				rivalries[p.name][o.name] = h2h
+			}
+		}
+	}
+
+	for name, opponents := range rivalries {
+		for opponent, h2h := range opponents {
+			h2h.AveragePlacementGap = float32(gaps[name][opponent]) / float32(h2h.Meetings)
+			opponents[opponent] = h2h
+		}
+	}
+
+	return rivalries
+}
@@ -0,0 +1,108 @@
+package leaderboards_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+func TestRivalries(t *testing.T) {
+	s, srv := newTestServer(t)
+	defer srv.Close()
+
+	withHistory(t, s, srv, tft.QueueRanked, tft.QueueRanked, tft.QueueRanked)
+
+	// Tactician One beats Tactician Two in the middle match.
+	m := &srv.Fixtures.Matches[1]
+	m.Info.Participants = append([]tft.Participant(nil), m.Info.Participants...)
+	for i := range m.Info.Participants {
+		switch p := &m.Info.Participants[i]; p.PUUID {
+		case puuidOne:
+			p.Placement = 1
+		case puuidTwo:
+			p.Placement = 6
+		}
+	}
+
+	out, err := s.GetResults(context.Background(), []string{puuidOne, puuidTwo}, &leaderboards.GetResultsArgs{GameLimit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rivalries := leaderboards.CalculateRivalries(leaderboards.NameResults{
+		"Tactician One": out[puuidOne],
+		"Tactician Two": out[puuidTwo],
+	})
+
+	newest := leaderboards.UnixMS(srv.Fixtures.Matches[0].Info.GameTimestamp)
+
+	tests := []struct {
+		player, opponent string
+		want             leaderboards.HeadToHead
+	}{
+		{
+			player:   "Tactician One",
+			opponent: "Tactician Two",
+			want:     leaderboards.HeadToHead{Wins: 1, Losses: 2, Meetings: 3, AveragePlacementGap: -5.0 / 3, LastMeeting: newest, LastMatchID: "NA1_1003"},
+		},
+		{
+			player:   "Tactician Two",
+			opponent: "Tactician One",
+			want:     leaderboards.HeadToHead{Wins: 2, Losses: 1, Meetings: 3, AveragePlacementGap: 5.0 / 3, LastMeeting: newest, LastMatchID: "NA1_1003"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := rivalries[tt.player][tt.opponent]
+		if !ok {
+			t.Errorf("no rivalry between %s and %s", tt.player, tt.opponent)
+			continue
+		}
+
+		if got.Wins != tt.want.Wins || got.Losses != tt.want.Losses || got.Ties != tt.want.Ties || got.Meetings != tt.want.Meetings ||
+			!approx(got.AveragePlacementGap, tt.want.AveragePlacementGap) ||
+			!got.LastMeeting.Equal(tt.want.LastMeeting) || got.LastMatchID != tt.want.LastMatchID {
+			t.Errorf("%s vs %s = %+v, want %+v", tt.player, tt.opponent, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateRivalries(t *testing.T) {
+	day := time.Date(2019, 11, 4, 12, 0, 0, 0, time.UTC)
+
+	result := func(id string, placement int) leaderboards.Result {
+		return leaderboards.Result{MatchID: id, StartedAt: day, Participant: tft.Participant{Placement: placement}}
+	}
+
+	rivalries := leaderboards.CalculateRivalries(leaderboards.NameResults{
+		// Double Up partners share a placement.
+		"Ada": {result("duo", 2), result("solo", 1), result("trio", 3)},
+		"Bo":  {result("duo", 2), result("trio", 1)},
+		"Cy":  {result("trio", 5), result("alone", 1)},
+	})
+
+	tests := []struct {
+		player, opponent string
+		want             leaderboards.HeadToHead
+	}{
+		{player: "Ada", opponent: "Bo", want: leaderboards.HeadToHead{Losses: 1, Ties: 1, Meetings: 2, AveragePlacementGap: -1}},
+		{player: "Bo", opponent: "Ada", want: leaderboards.HeadToHead{Wins: 1, Ties: 1, Meetings: 2, AveragePlacementGap: 1}},
+		{player: "Ada", opponent: "Cy", want: leaderboards.HeadToHead{Wins: 1, Meetings: 1, AveragePlacementGap: 2}},
+		{player: "Cy", opponent: "Bo", want: leaderboards.HeadToHead{Losses: 1, Meetings: 1, AveragePlacementGap: -4}},
+	}
+
+	for _, tt := range tests {
+		got := rivalries[tt.player][tt.opponent]
+		if got.Wins != tt.want.Wins || got.Losses != tt.want.Losses || got.Ties != tt.want.Ties ||
+			got.Meetings != tt.want.Meetings || !approx(got.AveragePlacementGap, tt.want.AveragePlacementGap) {
+			t.Errorf("%s vs %s = %+v, want %+v", tt.player, tt.opponent, got, tt.want)
+		}
+	}
+
+	if n := len(rivalries["Cy"]); n != 2 {
+		t.Errorf("Cy has %d rivals, want 2", n)
+	}
+}
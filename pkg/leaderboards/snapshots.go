@@ -0,0 +1,212 @@
+package leaderboards
+
+import (
+	"context"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// LeagueSnapshot is a member's standing in a queue at a point in time.
+type LeagueSnapshot struct {
+	PUUID        string    `json:"puuid"`
+	QueueType    string    `json:"queueType"`
+	Tier         string    `json:"tier"`
+	Rank         string    `json:"rank"`
+	LeaguePoints int       `json:"leaguePoints"`
+	Wins         int       `json:"wins"`
+	Losses       int       `json:"losses"`
+	TakenAt      time.Time `json:"takenAt"`
+}
+
+// SnapshotStore persists LeagueSnapshots.
+type SnapshotStore interface {
+	PutSnapshots(ctx context.Context, snaps []LeagueSnapshot) error
+	// ListSnapshots returns a member's snapshots of a queue taken between
+	// from and to inclusive, oldest first. Zero times are unbounded.
+	ListSnapshots(ctx context.Context, puuid, queueType string, from, to time.Time) ([]LeagueSnapshot, error)
+}
+
+// LPDelta is the change in a member's standing over a window.
+type LPDelta struct {
+	From LeagueSnapshot `json:"from"`
+	To   LeagueSnapshot `json:"to"`
+	// LP gained, counting promotions as the LP needed to reach the new rank.
+	LP     int `json:"lp"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+}
+
+// snapshotsOf a member's Leagues.
+func snapshotsOf(smnr *Summoner, takenAt time.Time) []LeagueSnapshot {
+	var snaps []LeagueSnapshot
+	for _, le := range smnr.Leagues {
+		snaps = append(snaps, LeagueSnapshot{
+			PUUID:        smnr.PUUID,
+			QueueType:    le.QueueType,
+			Tier:         le.Tier,
+			Rank:         le.Rank,
+			LeaguePoints: le.LeaguePoints,
+			Wins:         le.Wins,
+			Losses:       le.Losses,
+			TakenAt:      takenAt,
+		})
+	}
+
+	return snaps
+}
+
+// SnapshotLeaderboard refreshes a Leaderboard and records its members'
+// standing in every queue. Members that could not be refreshed are not
+// recorded and are reported by a *tft.BatchError.
+func (s *Server) SnapshotLeaderboard(ctx context.Context, id string) ([]LeagueSnapshot, error) {
+	if s.Snapshots == nil {
+		return nil, errors.New("snapshots are not configured")
+	}
+
+	board, rerr := s.RefreshLeaderboard(ctx, id)
+	if rerr != nil && !IsPartial(rerr) {
+		return nil, rerr
+	}
+
+	var (
+		failed  = failedItems(rerr)
+		takenAt = time.Now().UTC()
+		snaps   []LeagueSnapshot
+	)
+
+	for _, smnr := range board.Summoners {
+		smnr := smnr
+		// Members that failed to refresh keep stale Leagues.
+		if failed[smnr.Name] {
+			continue
+		}
+
+		snaps = append(snaps, snapshotsOf(&smnr, takenAt)...)
+	}
+
+	if len(snaps) > 0 {
+		if err := s.Snapshots.PutSnapshots(ctx, snaps); err != nil {
+			return nil, errors.Wrap(err, "put snapshots failed")
+		}
+	}
+
+	return snaps, rerr
+}
+
+// SnapshotLeaderboards snapshots every Leaderboard, continuing past failures.
+func (s *Server) SnapshotLeaderboards(ctx context.Context) error {
+	boards, err := s.Storage.ListLeaderboards(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list leaderboards failed")
+	}
+
+	failures := make(map[string]error)
+	for _, board := range boards {
+		if _, err := s.SnapshotLeaderboard(ctx, board.ID); err != nil {
+			failures[board.ID] = err
+		}
+	}
+
+	return batchError(failures)
+}
+
+// RunSnapshots snapshots every Leaderboard each interval until ctx is done.
+// Errors are passed to onErr, which may be nil.
+func (s *Server) RunSnapshots(ctx context.Context, interval time.Duration, onErr func(error)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := s.SnapshotLeaderboards(ctx); err != nil && onErr != nil {
+			onErr(err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// GetTimeline of a member's standing in a queue between from and to.
+func (s *Server) GetTimeline(ctx context.Context, puuid, queueType string, from, to time.Time) ([]LeagueSnapshot, error) {
+	if s.Snapshots == nil {
+		return nil, errors.New("snapshots are not configured")
+	}
+
+	snaps, err := s.Snapshots.ListSnapshots(ctx, puuid, queueType, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "list snapshots failed")
+	}
+
+	return snaps, nil
+}
+
+// GetLPDelta of a member in a queue between from and to.
+// Nil is returned if fewer than two snapshots were taken in the window.
+func (s *Server) GetLPDelta(ctx context.Context, puuid, queueType string, from, to time.Time) (*LPDelta, error) {
+	snaps, err := s.GetTimeline(ctx, puuid, queueType, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return CalculateLPDelta(snaps), nil
+}
+
+// GetLPDeltas of a Leaderboard's members in a queue between from and to.
+// Members without enough snapshots are omitted.
+func (s *Server) GetLPDeltas(ctx context.Context, id, queueType string, from, to time.Time) (map[string]LPDelta, error) {
+	board, err := s.Storage.GetLeaderboard(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get leaderboard failed")
+	}
+
+	deltas := make(map[string]LPDelta) // Key = Summoner.Name
+	for _, smnr := range board.Summoners {
+		d, err := s.GetLPDelta(ctx, smnr.PUUID, queueType, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		if d != nil {
+			deltas[smnr.Name] = *d
+		}
+	}
+
+	return deltas, nil
+}
+
+// CalculateLPDelta between the first and last of a member's snapshots,
+// which must be sorted oldest first.
+func CalculateLPDelta(snaps []LeagueSnapshot) *LPDelta {
+	if len(snaps) < 2 {
+		return nil
+	}
+
+	first, last := snaps[0], snaps[len(snaps)-1]
+
+	return &LPDelta{
+		From:   first,
+		To:     last,
+		LP:     LadderLP(last.Tier, last.Rank, last.LeaguePoints) - LadderLP(first.Tier, first.Rank, first.LeaguePoints),
+		Wins:   last.Wins - first.Wins,
+		Losses: last.Losses - first.Losses,
+	}
+}
+
+// failedItems of a *tft.BatchError.
+func failedItems(err error) map[string]bool {
+	failed := make(map[string]bool)
+
+	var batch *tft.BatchError
+	if errors.As(err, &batch) {
+		for k := range batch.Errors {
+			failed[k] = true
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,109 @@
+package leaderboards_test
+
+import (
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+func TestLadderLP(t *testing.T) {
+	tests := []struct {
+		name       string
+		tier, rank string
+		lp         int
+		want       int
+	}{
+		{name: "bottom of the ladder", tier: tft.TierIron, rank: tft.DivisionIV, lp: 0, want: 0},
+		{name: "divisions", tier: tft.TierIron, rank: tft.DivisionII, lp: 50, want: 250},
+		{name: "tiers", tier: tft.TierGold, rank: tft.DivisionIV, lp: 10, want: 1210},
+		{name: "emerald", tier: tft.TierEmerald, rank: tft.DivisionI, lp: 99, want: 2399},
+		{name: "diamond I", tier: tft.TierDiamond, rank: tft.DivisionI, lp: 99, want: 2799},
+		{name: "master", tier: tft.TierMaster, rank: tft.DivisionI, lp: 0, want: 2800},
+		{name: "apex tiers share LP", tier: tft.TierChallenger, rank: tft.DivisionI, lp: 900, want: 3700},
+		{name: "grandmaster", tier: tft.TierGrandmaster, rank: tft.DivisionI, lp: 500, want: 3300},
+		{name: "unranked", lp: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaderboards.LadderLP(tt.tier, tt.rank, tt.lp); got != tt.want {
+				t.Errorf("LadderLP(%s, %s, %d) = %d, want %d", tt.tier, tt.rank, tt.lp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateLPDelta(t *testing.T) {
+	snap := func(tier, rank string, lp, wins, losses int) leaderboards.LeagueSnapshot {
+		return leaderboards.LeagueSnapshot{Tier: tier, Rank: rank, LeaguePoints: lp, Wins: wins, Losses: losses}
+	}
+
+	tests := []struct {
+		name  string
+		snaps []leaderboards.LeagueSnapshot
+		want  *leaderboards.LPDelta
+	}{
+		{
+			name: "no snapshots",
+		},
+		{
+			name:  "one snapshot",
+			snaps: []leaderboards.LeagueSnapshot{snap(tft.TierGold, tft.DivisionII, 50, 10, 10)},
+		},
+		{
+			name:  "within a division",
+			snaps: []leaderboards.LeagueSnapshot{snap(tft.TierGold, tft.DivisionII, 20, 10, 10), snap(tft.TierGold, tft.DivisionII, 75, 12, 10)},
+			want:  &leaderboards.LPDelta{LP: 55, Wins: 2},
+		},
+		{
+			name: "division promotion",
+			snaps: []leaderboards.LeagueSnapshot{
+				snap(tft.TierGold, tft.DivisionII, 80, 10, 10),
+				snap(tft.TierGold, tft.DivisionII, 95, 11, 10),
+				snap(tft.TierGold, tft.DivisionI, 10, 12, 10),
+			},
+			want: &leaderboards.LPDelta{LP: 30, Wins: 2},
+		},
+		{
+			name:  "tier demotion",
+			snaps: []leaderboards.LeagueSnapshot{snap(tft.TierPlatinum, tft.DivisionIV, 0, 10, 10), snap(tft.TierGold, tft.DivisionI, 70, 10, 11)},
+			want:  &leaderboards.LPDelta{LP: -30, Losses: 1},
+		},
+		{
+			name:  "promotion to master",
+			snaps: []leaderboards.LeagueSnapshot{snap(tft.TierDiamond, tft.DivisionI, 80, 10, 10), snap(tft.TierMaster, tft.DivisionI, 40, 11, 10)},
+			want:  &leaderboards.LPDelta{LP: 60, Wins: 1},
+		},
+		{
+			name:  "apex promotion",
+			snaps: []leaderboards.LeagueSnapshot{snap(tft.TierMaster, tft.DivisionI, 480, 10, 10), snap(tft.TierGrandmaster, tft.DivisionI, 520, 11, 10)},
+			want:  &leaderboards.LPDelta{LP: 40, Wins: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := leaderboards.CalculateLPDelta(tt.snaps)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("got %+v, want nil", got)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatal("got nil")
+			}
+
+			if got.LP != tt.want.LP || got.Wins != tt.want.Wins || got.Losses != tt.want.Losses {
+				t.Errorf("got LP %d, %dW %dL, want LP %d, %dW %dL", got.LP, got.Wins, got.Losses, tt.want.LP, tt.want.Wins, tt.want.Losses)
+			}
+
+			if got.From != tt.snaps[0] || got.To != tt.snaps[len(tt.snaps)-1] {
+				t.Errorf("got %+v to %+v, want the first and last snapshots", got.From, got.To)
+			}
+		})
+	}
+}
@@ -2,33 +2,46 @@ package leaderboards
 
 import (
 	"context"
+	"math"
+	"sort"
 
-	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/alee792/teamfit/pkg/staticdata"
 )
 
 // Stats are aggregations of game results.
 type Stats struct {
-	Games             int     `json:"games"`
-	DamageDealt       int     `json:"damageDealt"`
-	PlayersEliminated int     `json:"playersEliminated"`
-	BoardValue        int     `json:"boardValue"`
-	Wins              int     `json:"wins"`
-	TopFours          int     `json:"topFours"`
-	AverageFinish     float32 `json:"averageFinish"`
+	Games             int `json:"games"`
+	DamageDealt       int `json:"damageDealt"`
+	PlayersEliminated int `json:"playersEliminated"`
+	// BoardValue is the total BoardGold of every game.
+	BoardValue       int     `json:"boardValue"`
+	AverageBoardGold float32 `json:"averageBoardGold"`
+	PeakBoardGold    int     `json:"peakBoardGold"`
+	Wins             int     `json:"wins"`
+	TopFours         int     `json:"topFours"`
+	AverageFinish    float32 `json:"averageFinish"`
+	TopFourRate      float32 `json:"topFourRate"`
+	WinRate          float32 `json:"winRate"`
+	// Placements counts finishes by place, i.e. Placements[0] is wins.
+	Placements   [8]int  `json:"placements"`
+	MedianFinish float32 `json:"medianFinish"`
+	StdDevFinish float32 `json:"stdDevFinish"`
+	Streaks      Streaks `json:"streaks"`
+	Form         Form    `json:"form"`
 }
 
-func getBoardValue(uu []tft.Unit) int {
-	var val int
-	for _, u := range uu {
-		val += u.Tier * u.Rarity
-	}
-
-	return val
+// BoardGold is the gold a result's final board cost to build, valued with
+// the static data for its set and patch. static may be nil.
+func BoardGold(r Result, static *staticdata.Store) int {
+	return static.BoardGold(r.Set, r.GameVersion, r.Units)
 }
 
 // GetStatsArgs is a request for aggregated results.
 type GetStatsArgs struct {
 	GameLimit int
+	Window
+	// Queues to include. See GetResultsArgs.
+	Queues []int
 }
 
 // GetStatsResponse returns aggregated results.
@@ -40,24 +53,30 @@ type GetStatsResponse struct {
 func (s *Server) GetStats(ctx context.Context, names []string, in *GetStatsArgs) (map[string]Stats, error) {
 	out, err := s.GetResultsFromNames(ctx, names, &GetResultsArgs{
 		GameLimit: in.GameLimit,
+		After:     in.Since,
+		Before:    in.Until,
+		Queues:    in.Queues,
+		Period:    in.Period,
 	})
-	if err != nil {
+	if err != nil && !IsPartial(err) {
 		return nil, err
 	}
 
 	var stats = make(map[string]Stats)
 	for n, rr := range out {
-		stats[n] = CalculateStats(rr)
+		stats[n] = CalculateStats(rr, s.Static)
 	}
 
-	return stats, nil
+	return stats, err
 }
 
 // CalculateStats for a set of results.
-func CalculateStats(rr []Result) Stats {
+// Boards are valued with static, which may be nil.
+func CalculateStats(rr []Result, static *staticdata.Store) Stats {
 	var (
 		stat     Stats
 		finishes float32
+		places   []int
 	)
 
 	if len(rr) == 0 {
@@ -67,7 +86,12 @@ func CalculateStats(rr []Result) Stats {
 	for _, r := range rr {
 		stat.DamageDealt += r.TotalDamageToPlayers
 		stat.PlayersEliminated += r.PlayersEliminated
-		stat.BoardValue += getBoardValue(r.Units)
+		gold := BoardGold(r, static)
+		stat.BoardValue += gold
+		if gold > stat.PeakBoardGold {
+			stat.PeakBoardGold = gold
+		}
+
 		// Must divide by total games before returning!
 		finishes += float32(r.Placement)
 
@@ -75,13 +99,57 @@ func CalculateStats(rr []Result) Stats {
 		case place == 1:
 			stat.TopFours++
 			stat.Wins++
-		case place > 5:
+		case place <= 4:
 			stat.TopFours++
 		}
+
+		if place := r.Placement; place >= 1 && place <= len(stat.Placements) {
+			stat.Placements[place-1]++
+		}
+
+		places = append(places, r.Placement)
 	}
 
 	stat.Games = len(rr)
 	stat.AverageFinish = finishes / float32(len(rr))
+	stat.TopFourRate = float32(stat.TopFours) / float32(len(rr))
+	stat.WinRate = float32(stat.Wins) / float32(len(rr))
+	stat.MedianFinish = median(places)
+	stat.StdDevFinish = stdDev(places, stat.AverageFinish)
+	stat.AverageBoardGold = float32(stat.BoardValue) / float32(len(rr))
+	stat.Streaks = CalculateStreaks(rr)
+	stat.Form = CalculateForm(rr)
 
 	return stat
 }
+
+// median of placements. places is sorted in place.
+func median(places []int) float32 {
+	if len(places) == 0 {
+		return 0
+	}
+
+	sort.Ints(places)
+
+	mid := len(places) / 2
+	if len(places)%2 == 1 {
+		return float32(places[mid])
+	}
+
+	return float32(places[mid-1]+places[mid]) / 2
+}
+
+// stdDev is the population standard deviation of placements.
+func stdDev(places []int, mean float32) float32 {
+	if len(places) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range places {
+		d := float64(p) - float64(mean)
+		sum += d * d
+	}
+
+	return float32(math.Sqrt(sum / float64(len(places))))
+}
@@ -0,0 +1,140 @@
+package leaderboards_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft/tfttest"
+)
+
+// fixtureResults by placement from the fixture matches.
+func fixtureResults(t *testing.T) map[int]leaderboards.Result {
+	t.Helper()
+
+	f, err := tfttest.LoadFixtures("../tft/tfttest/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := make(map[int]leaderboards.Result)
+	for _, m := range f.Matches {
+		for _, p := range m.Info.Participants {
+			out[p.Placement] = leaderboards.Result{
+				MatchID:     m.Metadata.MatchID,
+				StartedAt:   leaderboards.UnixMS(m.Info.GameTimestamp),
+				Set:         m.Info.Set,
+				GameVersion: m.Info.GameVersion,
+				QueueID:     m.Info.QueueID,
+				Participant: p,
+			}
+		}
+	}
+
+	return out
+}
+
+func TestCalculateStats(t *testing.T) {
+	results := fixtureResults(t)
+
+	tests := []struct {
+		name         string
+		places       []int
+		topFours     int
+		winRate      float32
+		placements   [8]int
+		medianFinish float32
+		stdDevFinish float32
+	}{
+		{
+			name: "no games",
+		},
+		{
+			name:         "win",
+			places:       []int{1},
+			topFours:     1,
+			winRate:      1,
+			placements:   [8]int{1, 0, 0, 0, 0, 0, 0, 0},
+			medianFinish: 1,
+		},
+		{
+			name:         "whole lobby",
+			places:       []int{1, 2, 3, 4, 5, 6, 7, 8},
+			topFours:     4,
+			winRate:      0.125,
+			placements:   [8]int{1, 1, 1, 1, 1, 1, 1, 1},
+			medianFinish: 4.5,
+			stdDevFinish: 2.2913,
+		},
+		{
+			// Top fours were once counted as placements above 5.
+			name:         "bottom four are not top fours",
+			places:       []int{5, 6, 7, 8},
+			placements:   [8]int{0, 0, 0, 0, 1, 1, 1, 1},
+			medianFinish: 6.5,
+			stdDevFinish: 1.1180,
+		},
+		{
+			name:         "second to fourth are top fours",
+			places:       []int{2, 3, 4},
+			topFours:     3,
+			placements:   [8]int{0, 1, 1, 1, 0, 0, 0, 0},
+			medianFinish: 3,
+			stdDevFinish: 0.8165,
+		},
+		{
+			name:         "even games",
+			places:       []int{8, 1},
+			topFours:     1,
+			winRate:      0.5,
+			placements:   [8]int{1, 0, 0, 0, 0, 0, 0, 1},
+			medianFinish: 4.5,
+			stdDevFinish: 3.5,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var rr []leaderboards.Result
+			for _, place := range tt.places {
+				r, ok := results[place]
+				if !ok {
+					t.Fatalf("no fixture result placed %d", place)
+				}
+
+				rr = append(rr, r)
+			}
+
+			got := leaderboards.CalculateStats(rr, nil)
+
+			if got.Games != len(tt.places) {
+				t.Errorf("Games = %d, want %d", got.Games, len(tt.places))
+			}
+
+			if got.TopFours != tt.topFours {
+				t.Errorf("TopFours = %d, want %d", got.TopFours, tt.topFours)
+			}
+
+			if !approx(got.WinRate, tt.winRate) {
+				t.Errorf("WinRate = %v, want %v", got.WinRate, tt.winRate)
+			}
+
+			if got.Placements != tt.placements {
+				t.Errorf("Placements = %v, want %v", got.Placements, tt.placements)
+			}
+
+			if !approx(got.MedianFinish, tt.medianFinish) {
+				t.Errorf("MedianFinish = %v, want %v", got.MedianFinish, tt.medianFinish)
+			}
+
+			if !approx(got.StdDevFinish, tt.stdDevFinish) {
+				t.Errorf("StdDevFinish = %v, want %v", got.StdDevFinish, tt.stdDevFinish)
+			}
+		})
+	}
+}
+
+func approx(got, want float32) bool {
+	return math.Abs(float64(got-want)) < 1e-4
+}
@@ -0,0 +1,128 @@
+package leaderboards
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+// Period is a window relative to the newest game in a set of results.
+type Period string
+
+// Periods supported by Window.
+const (
+	// PeriodPatch keeps games from the newest game's patch.
+	PeriodPatch Period = "patch"
+	// PeriodSeason keeps games from the newest game's set.
+	PeriodSeason Period = "season"
+)
+
+// dateLayout of dates like 2019-11-04.
+const dateLayout = "2006-01-02"
+
+// Window of games to include. Zero values are unbounded.
+type Window struct {
+	Since  time.Time
+	Until  time.Time
+	Period Period
+}
+
+// ParseWindow parses since and until, which may be RFC 3339 times, dates like
+// 2019-11-04, or durations before now like 7d, 2w or 12h. since may also be a
+// Period, i.e. "patch" or "season". A date until includes the whole day.
+// Empty values are unbounded.
+func ParseWindow(since, until string, now time.Time) (Window, error) {
+	var (
+		w   Window
+		err error
+	)
+
+	switch p := Period(strings.ToLower(strings.TrimSpace(since))); p {
+	case PeriodPatch, PeriodSeason:
+		w.Period = p
+	default:
+		if w.Since, err = ParseTime(since, now); err != nil {
+			return w, err
+		}
+	}
+
+	if w.Until, err = parseUntil(until, now); err != nil {
+		return w, err
+	}
+
+	if !w.Since.IsZero() && !w.Until.IsZero() && w.Since.After(w.Until) {
+		return w, fmt.Errorf("since %q is after until %q", since, until)
+	}
+
+	return w, nil
+}
+
+// parseUntil is ParseTime, except a date is the end of that day.
+func parseUntil(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, strings.TrimSpace(s)); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+
+	return ParseTime(s, now)
+}
+
+// ParseTime parses an RFC 3339 time, a date or a duration before now.
+// Durations support d and w units in addition to Go's, e.g. 7d.
+// An empty string is the zero time.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, nil
+	}
+
+	d, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %q", s)
+	}
+
+	return now.Add(-d), nil
+}
+
+// parseDuration like time.ParseDuration, plus days and weeks.
+func parseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(n) * unit, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// periodOf a match, i.e. its set and, for PeriodPatch, its patch.
+func periodOf(period Period, m *tft.Match) string {
+	switch period {
+	case PeriodPatch:
+		return fmt.Sprintf("%d/%s", m.Info.Set, tft.ParsePatch(m.Info.GameVersion))
+	case PeriodSeason:
+		return strconv.Itoa(m.Info.Set)
+	}
+
+	return ""
+}
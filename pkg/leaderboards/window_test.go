@@ -0,0 +1,81 @@
+package leaderboards_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+)
+
+func TestParseWindow(t *testing.T) {
+	now := time.Date(2019, 11, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		since, until string
+		want         leaderboards.Window
+		wantErr      bool
+	}{
+		{
+			name: "unbounded",
+		},
+		{
+			name:  "duration",
+			since: "7d",
+			want:  leaderboards.Window{Since: now.AddDate(0, 0, -7)},
+		},
+		{
+			name:  "period",
+			since: "patch",
+			want:  leaderboards.Window{Period: leaderboards.PeriodPatch},
+		},
+		{
+			name:  "date until includes the day",
+			since: "2019-11-04",
+			until: "2019-11-04",
+			want: leaderboards.Window{
+				Since: time.Date(2019, 11, 4, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2019, 11, 5, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			},
+		},
+		{
+			name:  "time until is exact",
+			until: "2019-11-04T10:00:00Z",
+			want:  leaderboards.Window{Until: time.Date(2019, 11, 4, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "since after until",
+			since:   "2019-11-05",
+			until:   "2019-11-04",
+			wantErr: true,
+		},
+		{
+			name:    "duration since after until",
+			since:   "1d",
+			until:   "2d",
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			since:   "yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := leaderboards.ParseWindow(tt.since, tt.until, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) || got.Period != tt.want.Period {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
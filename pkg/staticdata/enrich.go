@@ -0,0 +1,77 @@
+package staticdata
+
+import (
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+// EnrichParticipant returns a copy of p with display names, costs and icons
+// set on its Units and Traits. p is not modified, so cached matches can be
+// enriched safely. Definitions missing from the Bundle are left blank.
+func (b *Bundle) EnrichParticipant(p tft.Participant) tft.Participant {
+	if b == nil {
+		return p
+	}
+
+	units := make([]tft.Unit, len(p.Units))
+	for i, u := range p.Units {
+		units[i] = b.EnrichUnit(u)
+	}
+
+	traits := make([]tft.Trait, len(p.Traits))
+	for i, t := range p.Traits {
+		traits[i] = b.EnrichTrait(t)
+	}
+
+	p.Units = units
+	p.Traits = traits
+
+	return p
+}
+
+// EnrichUnit with its champion's name, cost and icon and its items' names.
+func (b *Bundle) EnrichUnit(u tft.Unit) tft.Unit {
+	if c, ok := b.Champion(u.CharacterID); ok {
+		u.DisplayName = c.Name
+		u.Cost = c.Cost
+		u.Icon = c.Icon
+	}
+
+	// Riot may send both Items and ItemNames for the same items.
+	var names []string
+	if len(u.ItemNames) > 0 {
+		for _, apiName := range u.ItemNames {
+			if i, ok := b.ItemByName(apiName); ok {
+				names = append(names, i.Name)
+			}
+		}
+	} else {
+		for _, id := range u.Items {
+			if i, ok := b.Item(id); ok {
+				names = append(names, i.Name)
+			}
+		}
+	}
+
+	u.ItemDisplayNames = names
+
+	return u
+}
+
+// EnrichTrait with its name and icon.
+func (b *Bundle) EnrichTrait(t tft.Trait) tft.Trait {
+	if d, ok := b.Trait(t.Name); ok {
+		t.DisplayName = d.Name
+		t.Icon = d.Icon
+	}
+
+	return t
+}
+
+// Enrich a match's participant using the Bundle for the match's set and patch.
+func (s *Store) Enrich(info *tft.Info, p tft.Participant) tft.Participant {
+	if s == nil || info == nil {
+		return p
+	}
+
+	return s.Lookup(info.Set, info.GameVersion).EnrichParticipant(p)
+}
@@ -0,0 +1,141 @@
+package staticdata
+
+import (
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+// DefaultComponentGold is the gold a Bundle values an item component at
+// unless it sets ComponentGold. Components aren't bought, so this is an
+// estimate of what they're worth relative to units.
+const DefaultComponentGold = 3
+
+// Copies of a unit needed for a star level, i.e. 1, 3 and 9.
+func Copies(star int) int {
+	copies := 1
+	for i := 1; i < star; i++ {
+		copies *= 3
+	}
+
+	return copies
+}
+
+// RarityCost maps a unit's rarity to its shop cost. Sets 1 to 3 numbered
+// rarities 0 to 4, later sets skip rarities, e.g. 0, 1, 2, 4 and 6.
+func RarityCost(set, rarity int) int {
+	if set > 0 && set <= 3 {
+		return rarity + 1
+	}
+
+	switch {
+	case rarity <= 2:
+		return rarity + 1
+	case rarity <= 4:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// UnitGold is what a unit cost to build: copies for its star level × its cost,
+// plus its item components. b may be nil, in which case the unit's Cost,
+// or its rarity, and the shape of its item IDs are used.
+func UnitGold(b *Bundle, set int, u tft.Unit) int {
+	cost := u.Cost
+	if b != nil {
+		if c, ok := b.Champion(u.CharacterID); ok && c.Cost > 0 {
+			cost = c.Cost
+		}
+	}
+
+	if cost == 0 {
+		cost = RarityCost(set, u.Rarity)
+	}
+
+	star := u.Tier
+	if star < 1 {
+		star = 1
+	}
+
+	return Copies(star)*cost + Components(b, u)*b.componentGold()
+}
+
+// BoardGold is the sum of UnitGold for a board.
+func BoardGold(b *Bundle, set int, uu []tft.Unit) int {
+	var gold int
+	for _, u := range uu {
+		gold += UnitGold(b, set, u)
+	}
+
+	return gold
+}
+
+// Components held by a unit. See ItemComponents.
+// Riot may send both Items and ItemNames for the same items, so
+// ItemNames are preferred when present.
+func Components(b *Bundle, u tft.Unit) int {
+	var n int
+	if len(u.ItemNames) > 0 {
+		for _, apiName := range u.ItemNames {
+			n += ItemNameComponents(b, apiName)
+		}
+
+		return n
+	}
+
+	for _, id := range u.Items {
+		n += ItemComponents(b, id)
+	}
+
+	return n
+}
+
+// ItemComponents an item is built from, or 1 for a component. Items missing
+// from b, or when b is nil, are assumed to be components if their ID is a
+// single digit, as in sets 1 to 3, and completed items otherwise.
+func ItemComponents(b *Bundle, id int) int {
+	if b != nil {
+		if i, ok := b.Item(id); ok {
+			return i.components()
+		}
+	}
+
+	if id < 10 {
+		return 1
+	}
+
+	return 2
+}
+
+// ItemNameComponents is ItemComponents for items identified by API name.
+// Items missing from b are assumed to be completed items.
+func ItemNameComponents(b *Bundle, apiName string) int {
+	if b != nil {
+		if i, ok := b.ItemByName(apiName); ok {
+			return i.components()
+		}
+	}
+
+	return 2
+}
+
+// components an item is built from. Components count as one.
+func (i *Item) components() int {
+	if len(i.Components) == 0 {
+		return 1
+	}
+
+	return len(i.Components)
+}
+
+func (b *Bundle) componentGold() int {
+	if b == nil || b.ComponentGold <= 0 {
+		return DefaultComponentGold
+	}
+
+	return b.ComponentGold
+}
+
+// BoardGold of a board from a match, valued with the match's Bundle if any.
+func (s *Store) BoardGold(set int, gameVersion string, uu []tft.Unit) int {
+	return BoardGold(s.Lookup(set, gameVersion), set, uu)
+}
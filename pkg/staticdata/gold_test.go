@@ -0,0 +1,80 @@
+package staticdata
+
+import (
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+func TestUnitGold(t *testing.T) {
+	b := &Bundle{
+		Set: 2,
+		Champions: []Champion{
+			{ID: "TFT2_Ashe", Cost: 3},
+			{ID: "TFT2_Zed", Cost: 5},
+		},
+		Items: []Item{
+			{ID: 1, APIName: "TFT_Item_BFSword"},
+			{ID: 2, APIName: "TFT_Item_RecurveBow"},
+			{ID: 12, APIName: "TFT_Item_GiantSlayer", Components: []int{1, 2}},
+			// Radiant items are upgraded completed items.
+			{ID: 5012, APIName: "TFT5_Item_GiantSlayerRadiant", Components: []int{12}},
+		},
+	}
+	b.index()
+
+	tests := []struct {
+		name   string
+		bundle *Bundle
+		set    int
+		unit   tft.Unit
+		want   int
+	}{
+		{name: "1 star", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 1}, want: 3},
+		{name: "2 star", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 2}, want: 9},
+		{name: "3 star", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Zed", Tier: 3}, want: 45},
+		{name: "missing star level", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe"}, want: 3},
+		{name: "cost from rarity", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Unknown", Tier: 2, Rarity: 3}, want: 12},
+		{name: "component", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 1, Items: []int{1}}, want: 3 + 3},
+		{name: "completed item", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 1, Items: []int{12}}, want: 3 + 6},
+		{name: "completed and partial items", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 2, Items: []int{12, 2}}, want: 9 + 9},
+		{name: "upgraded item", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 1, ItemNames: []string{"TFT5_Item_GiantSlayerRadiant"}}, want: 3 + 3},
+		{
+			name:   "items named and numbered once",
+			bundle: b,
+			unit:   tft.Unit{CharacterID: "TFT2_Ashe", Tier: 1, Items: []int{12, 1}, ItemNames: []string{"TFT_Item_GiantSlayer", "TFT_Item_BFSword"}},
+			want:   3 + 9,
+		},
+		{name: "unknown named item", bundle: b, unit: tft.Unit{CharacterID: "TFT2_Ashe", Tier: 1, ItemNames: []string{"TFT_Item_Unknown"}}, want: 3 + 6},
+		{name: "without a bundle", unit: tft.Unit{Tier: 2, Rarity: 1, Items: []int{3, 23}}, want: 6 + 9},
+		{name: "without a bundle in later sets", set: 9, unit: tft.Unit{Tier: 1, Rarity: 6}, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := tt.set
+			if set == 0 {
+				set = 2
+			}
+
+			if got := UnitGold(tt.bundle, set, tt.unit); got != tt.want {
+				t.Errorf("UnitGold = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoardGold(t *testing.T) {
+	b := &Bundle{ComponentGold: 2}
+	b.index()
+
+	units := []tft.Unit{
+		{Tier: 1, Cost: 1},
+		{Tier: 2, Cost: 2, Items: []int{1, 2}},
+		{Tier: 3, Cost: 1, Items: []int{44}},
+	}
+
+	if got, want := BoardGold(b, 2, units), 1+(6+2*2)+(9+2*2); got != want {
+		t.Errorf("BoardGold = %d, want %d", got, want)
+	}
+}
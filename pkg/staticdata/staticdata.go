@@ -0,0 +1,227 @@
+// Package staticdata resolves the raw IDs in TFT matches, e.g. TFT3_Ahri or
+// item 44, to display names, costs and icons.
+//
+// Definitions are loaded from local JSON bundles, downloaded ahead of time
+// from Data Dragon or CommunityDragon and converted to a Bundle, one per set
+// and patch:
+//
+//	{
+//	  "version": "9.22",
+//	  "set": 2,
+//	  "champions": [{"id": "TFT2_Ashe", "name": "Ashe", "cost": 4, "icon": "...", "traits": ["Set2_Ranger"]}],
+//	  "traits": [{"id": "Set2_Ranger", "name": "Ranger", "icon": "..."}],
+//	  "items": [{"id": 44, "apiName": "TFT_Item_...", "name": "...", "icon": "...", "components": [4, 4]}],
+//	  "augments": [{"id": "TFT6_Augment_...", "name": "...", "icon": "...", "tier": 1}],
+//	  "componentGold": 3
+//	}
+package staticdata
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// Bundle of a set's definitions for a patch.
+type Bundle struct {
+	// Version is a major.minor patch, e.g. "9.22".
+	Version   string     `json:"version"`
+	Set       int        `json:"set"`
+	Champions []Champion `json:"champions"`
+	Traits    []Trait    `json:"traits"`
+	Items     []Item     `json:"items"`
+	Augments  []Augment  `json:"augments"`
+	// ComponentGold values item components. Defaults to DefaultComponentGold.
+	ComponentGold int `json:"componentGold,omitempty"`
+
+	champions map[string]*Champion // Key = Champion.ID
+	traits    map[string]*Trait    // Key = Trait.ID
+	items     map[int]*Item        // Key = Item.ID
+	itemNames map[string]*Item     // Key = Item.APIName
+	augments  map[string]*Augment  // Key = Augment.ID
+}
+
+// Champion is a unit's definition.
+type Champion struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Cost   int      `json:"cost"`
+	Icon   string   `json:"icon"`
+	Traits []string `json:"traits"`
+}
+
+type Trait struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Icon string `json:"icon"`
+}
+
+type Item struct {
+	ID      int    `json:"id"`
+	APIName string `json:"apiName"`
+	Name    string `json:"name"`
+	Icon    string `json:"icon"`
+	// Components that combine into the item. Empty for components themselves.
+	Components []int `json:"components"`
+}
+
+type Augment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Icon string `json:"icon"`
+	Tier int    `json:"tier"`
+}
+
+// index a Bundle's definitions for lookups.
+func (b *Bundle) index() {
+	b.champions = make(map[string]*Champion)
+	for i := range b.Champions {
+		b.champions[b.Champions[i].ID] = &b.Champions[i]
+	}
+
+	b.traits = make(map[string]*Trait)
+	for i := range b.Traits {
+		b.traits[b.Traits[i].ID] = &b.Traits[i]
+	}
+
+	b.items = make(map[int]*Item)
+	b.itemNames = make(map[string]*Item)
+	for i := range b.Items {
+		b.items[b.Items[i].ID] = &b.Items[i]
+		if b.Items[i].APIName != "" {
+			b.itemNames[b.Items[i].APIName] = &b.Items[i]
+		}
+	}
+
+	b.augments = make(map[string]*Augment)
+	for i := range b.Augments {
+		b.augments[b.Augments[i].ID] = &b.Augments[i]
+	}
+}
+
+// Champion by character ID, e.g. TFT3_Ahri.
+func (b *Bundle) Champion(id string) (*Champion, bool) {
+	c, ok := b.champions[id]
+	return c, ok
+}
+
+// Trait by ID, e.g. Set2_Ranger.
+func (b *Bundle) Trait(id string) (*Trait, bool) {
+	t, ok := b.traits[id]
+	return t, ok
+}
+
+// Item by numeric ID.
+func (b *Bundle) Item(id int) (*Item, bool) {
+	i, ok := b.items[id]
+	return i, ok
+}
+
+// ItemByName by API name, e.g. TFT_Item_InfinityEdge.
+func (b *Bundle) ItemByName(apiName string) (*Item, bool) {
+	i, ok := b.itemNames[apiName]
+	return i, ok
+}
+
+// Augment by ID.
+func (b *Bundle) Augment(id string) (*Augment, bool) {
+	a, ok := b.augments[id]
+	return a, ok
+}
+
+// Store of Bundles across sets and patches.
+type Store struct {
+	bundles map[int][]*Bundle // Key = Set, sorted by Version
+}
+
+// Load every *.json Bundle beneath dir.
+func Load(dir string) (*Store, error) {
+	s := &Store{
+		bundles: make(map[int][]*Bundle),
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		bb, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var b Bundle
+		if err := json.Unmarshal(bb, &b); err != nil {
+			return errors.Wrapf(err, "invalid bundle %s", path)
+		}
+
+		s.Add(&b)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Add a Bundle, replacing any Bundle of the same set and version.
+func (s *Store) Add(b *Bundle) {
+	b.index()
+
+	bundles := s.bundles[b.Set]
+	for i, prev := range bundles {
+		if prev.Version == b.Version {
+			bundles[i] = b
+			return
+		}
+	}
+
+	bundles = append(bundles, b)
+	sort.Slice(bundles, func(i, j int) bool {
+		return tft.ComparePatches(bundles[i].Version, bundles[j].Version) < 0
+	})
+
+	s.bundles[b.Set] = bundles
+}
+
+// Lookup the Bundle for a set and game version, e.g. tft.Info's Set and GameVersion.
+// It prefers the patch itself, then the latest earlier patch, then the set's
+// earliest patch. Nil is returned if the set has no Bundles or s is nil.
+func (s *Store) Lookup(set int, gameVersion string) *Bundle {
+	if s == nil {
+		return nil
+	}
+
+	bundles := s.bundles[set]
+	if len(bundles) == 0 {
+		return nil
+	}
+
+	patch := tft.ParsePatch(gameVersion)
+
+	var best *Bundle
+	for _, b := range bundles {
+		if patch != "" && tft.ComparePatches(b.Version, patch) > 0 {
+			break
+		}
+
+		best = b
+	}
+
+	if best == nil {
+		best = bundles[0]
+	}
+
+	return best
+}
@@ -0,0 +1,99 @@
+package jsonmap
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/pkg/errors"
+)
+
+var _ leaderboards.AwardStore = &AwardClient{}
+
+// AwardClient persists achievement awards in a JSON encoded map.
+type AwardClient struct {
+	// Path to the JSON encoded award map.
+	Path   string
+	Awards map[string]map[string][]leaderboards.Award // Key = Leaderboard ID, then PUUID
+	mux    *sync.Mutex
+}
+
+func NewAwardClient(path string) (*AwardClient, error) {
+	c := &AwardClient{
+		Path:   path,
+		Awards: make(map[string]map[string][]leaderboards.Award),
+		mux:    &sync.Mutex{},
+	}
+
+	ok, err := openFile(path)
+	if err != nil || !ok {
+		return c, err
+	}
+
+	if err := readFile(path, &c.Awards); err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", path)
+	}
+
+	return c, nil
+}
+
+func (c *AwardClient) GetAwards(ctx context.Context, boardID string) ([]leaderboards.Award, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var awards []leaderboards.Award
+	for puuid, aa := range c.Awards[boardID] {
+		awards = append(awards, withPUUID(puuid, aa)...)
+	}
+
+	sortAwards(awards)
+
+	return awards, nil
+}
+
+func (c *AwardClient) ListAwards(ctx context.Context, puuid string) ([]leaderboards.Award, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var awards []leaderboards.Award
+	for _, board := range c.Awards {
+		awards = append(awards, withPUUID(puuid, board[puuid])...)
+	}
+
+	sortAwards(awards)
+
+	return awards, nil
+}
+
+func (c *AwardClient) PutAwards(ctx context.Context, boardID string, awards []leaderboards.Award) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.Awards[boardID] == nil {
+		c.Awards[boardID] = make(map[string][]leaderboards.Award)
+	}
+
+	for _, aw := range awards {
+		c.Awards[boardID][aw.PUUID] = append(c.Awards[boardID][aw.PUUID], aw)
+	}
+
+	return writeFile(c.Path, &c.Awards)
+}
+
+// withPUUID restores the PUUID, which Award does not encode.
+func withPUUID(puuid string, aa []leaderboards.Award) []leaderboards.Award {
+	out := make([]leaderboards.Award, len(aa))
+	for i, aw := range aa {
+		aw.PUUID = puuid
+		out[i] = aw
+	}
+
+	return out
+}
+
+func sortAwards(awards []leaderboards.Award) {
+	sort.SliceStable(awards, func(i, j int) bool {
+		return awards[i].EarnedAt.Before(awards[j].EarnedAt)
+	})
+}
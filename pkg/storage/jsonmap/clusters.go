@@ -0,0 +1,71 @@
+package jsonmap
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+var _ leaderboards.ClusterStore = &ClusterClient{}
+
+// ClusterClient persists composition clusters in a JSON encoded map.
+type ClusterClient struct {
+	// Path to the JSON encoded cluster map.
+	Path     string
+	Clusters map[string]map[string]leaderboards.PatchClusters // Key = Leaderboard ID, then patch
+	mux      *sync.Mutex
+}
+
+func NewClusterClient(path string) (*ClusterClient, error) {
+	c := &ClusterClient{
+		Path:     path,
+		Clusters: make(map[string]map[string]leaderboards.PatchClusters),
+		mux:      &sync.Mutex{},
+	}
+
+	ok, err := openFile(path)
+	if err != nil || !ok {
+		return c, err
+	}
+
+	if err := readFile(path, &c.Clusters); err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", path)
+	}
+
+	return c, nil
+}
+
+func (c *ClusterClient) PutClusters(ctx context.Context, boardID string, clusters []leaderboards.PatchClusters) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.Clusters[boardID] == nil {
+		c.Clusters[boardID] = make(map[string]leaderboards.PatchClusters)
+	}
+
+	for _, pc := range clusters {
+		c.Clusters[boardID][pc.Patch] = pc
+	}
+
+	return writeFile(c.Path, &c.Clusters)
+}
+
+func (c *ClusterClient) ListClusters(ctx context.Context, boardID string) ([]leaderboards.PatchClusters, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var clusters []leaderboards.PatchClusters
+	for _, pc := range c.Clusters[boardID] {
+		clusters = append(clusters, pc)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return tft.ComparePatches(clusters[i].Patch, clusters[j].Patch) > 0
+	})
+
+	return clusters, nil
+}
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
 	"github.com/pkg/errors"
 )
 
@@ -30,15 +33,9 @@ func NewClient(path string) (*Client, error) {
 		fileMux:  &sync.Mutex{},
 	}
 
-	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0666)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	info, err := os.Stat(path)
-	if err != nil || info.Size() == 0 {
-		return c, nil
+	ok, err := openFile(path)
+	if err != nil || !ok {
+		return c, err
 	}
 
 	if err := c.read(); err != nil {
@@ -48,6 +45,7 @@ func NewClient(path string) (*Client, error) {
 	return c, nil
 }
 
+// CreateLeaderboard identified by its name.
 func (c *Client) CreateLeaderboard(ctx context.Context, board *leaderboards.Leaderboard) (*leaderboards.Leaderboard, error) {
 	c.boardMux.Lock()
 	defer c.boardMux.Unlock()
@@ -58,27 +56,100 @@ func (c *Client) CreateLeaderboard(ctx context.Context, board *leaderboards.Lead
 		return nil, err
 	}
 
-	return c.Boards[board.Name], nil
+	return c.board(board.Name), nil
 }
 
 func (c *Client) GetLeaderboard(ctx context.Context, id string) (*leaderboards.Leaderboard, error) {
 	c.boardMux.Lock()
 	defer c.boardMux.Unlock()
 
-	return c.Boards[id], nil
+	if _, ok := c.Boards[id]; !ok {
+		return nil, errors.Wrapf(leaderboards.ErrNotFound, "leaderboard %s", id)
+	}
+
+	return c.board(id), nil
+}
+
+// board by name with its ID set, which isn't persisted.
+// The caller must hold boardMux.
+func (c *Client) board(id string) *leaderboards.Leaderboard {
+	board := *c.Boards[id]
+	board.ID = id
+
+	return &board
+}
+
+// ListLeaderboards sorted by name. Boards are identified by name.
+func (c *Client) ListLeaderboards(ctx context.Context) ([]*leaderboards.Leaderboard, error) {
+	c.boardMux.Lock()
+	defer c.boardMux.Unlock()
+
+	var out []*leaderboards.Leaderboard
+	for id := range c.Boards {
+		out = append(out, c.board(id))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out, nil
+}
+
+func (c *Client) UpdateLeaderboard(ctx context.Context, id string, board *leaderboards.Leaderboard) (*leaderboards.Leaderboard, error) {
+	c.boardMux.Lock()
+	defer c.boardMux.Unlock()
+
+	if _, ok := c.Boards[id]; !ok {
+		return nil, errors.Wrapf(leaderboards.ErrNotFound, "leaderboard %s", id)
+	}
+
+	c.Boards[id] = board
+
+	if err := c.write(); err != nil {
+		return nil, err
+	}
+
+	return c.board(id), nil
 }
 
 func (c *Client) read() error {
 	c.fileMux.Lock()
 	defer c.fileMux.Unlock()
 
-	bb, err := ioutil.ReadFile(c.Path)
-	if err != nil {
+	if err := readFile(c.Path, &c.Boards); err != nil {
+		return err
+	}
+
+	// Summoners once embedded a single LeagueEntry.
+	var legacy map[string]struct {
+		Summoners map[string]tft.LeagueEntry
+	}
+
+	if err := readFile(c.Path, &legacy); err != nil {
 		return err
 	}
 
-	if err := json.NewDecoder(bytes.NewReader(bb)).Decode(&c.Boards); err != nil {
-		return errors.Wrapf(err, "could not decode file at %s", c.Path)
+	for id, board := range c.Boards {
+		for key, smnr := range board.Summoners {
+			le := legacy[id].Summoners[key]
+			if len(smnr.Leagues) > 0 || le.QueueType == "" {
+				continue
+			}
+
+			smnr.Leagues = map[string]tft.LeagueEntry{le.QueueType: le}
+			board.Summoners[key] = smnr
+		}
+	}
+
+	// Members were once keyed by name, which changes.
+	for _, board := range c.Boards {
+		smnrs := make(map[string]leaderboards.Summoner)
+		for _, smnr := range board.Summoners {
+			smnrs[smnr.PUUID] = smnr
+		}
+
+		board.Summoners = smnrs
 	}
 
 	return nil
@@ -88,18 +159,77 @@ func (c *Client) write() error {
 	c.fileMux.Lock()
 	defer c.fileMux.Unlock()
 
-	f, err := os.OpenFile(c.Path, os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	return writeFile(c.Path, &c.Boards)
+}
+
+// openFile ensures a file exists, reporting whether it has any content.
+func openFile(path string) (bool, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0666)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer f.Close()
 
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func readFile(path string, v interface{}) error {
+	bb, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(bb)).Decode(v); err != nil {
+		return errors.Wrapf(err, "could not decode file at %s", path)
+	}
+
+	return nil
+}
+
+// writeFile atomically, so a failed write leaves the previous file in place.
+func writeFile(path string, v interface{}) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if err := encodeFile(f, v); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return errors.Wrapf(err, "could not encode file at %s", path)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
+
+// encodeFile as indented JSON and flush it to disk.
+func encodeFile(f *os.File, v interface{}) error {
+	if err := f.Chmod(0644); err != nil {
+		return err
+	}
+
 	enc := json.NewEncoder(f)
 	enc.SetIndent("", "  ")
 
-	if err := enc.Encode(&c.Boards); err != nil {
-		return errors.Wrapf(err, "could not decode file at %s", c.Path)
+	if err := enc.Encode(v); err != nil {
+		return err
 	}
 
-	return nil
+	return f.Sync()
 }
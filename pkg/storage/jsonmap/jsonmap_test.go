@@ -0,0 +1,44 @@
+package jsonmap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileKeepsPreviousFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "boards.json")
+	if err := writeFile(path, map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Channels can't be encoded, so the write fails partway.
+	if err := writeFile(path, map[string]interface{}{"a": 2, "b": make(chan int)}); err == nil {
+		t.Fatal("expected an encoding error")
+	}
+
+	var got map[string]int
+	if err := readFile(path, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got["a"] != 1 {
+		t.Errorf("got %v, want the previous file", got)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 {
+		t.Errorf("got %d files, want temporary files removed", len(files))
+	}
+}
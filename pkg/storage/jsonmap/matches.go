@@ -0,0 +1,84 @@
+package jsonmap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+var _ leaderboards.MatchStore = &MatchClient{}
+
+// MatchClient caches matches as JSON files in a directory, one per match,
+// so caching a match only writes that match.
+type MatchClient struct {
+	// Dir of JSON encoded matches, named by Match ID, e.g. NA1_3206016526.json.
+	Dir string
+	mux *sync.RWMutex
+}
+
+func NewMatchClient(dir string) (*MatchClient, error) {
+	info, err := os.Stat(dir)
+	switch {
+	case err == nil && !info.IsDir():
+		return nil, errors.Errorf("match cache %s is not a directory", dir)
+	case err != nil && !os.IsNotExist(err):
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "unable to create %s", dir)
+	}
+
+	return &MatchClient{
+		Dir: dir,
+		mux: &sync.RWMutex{},
+	}, nil
+}
+
+func (c *MatchClient) GetMatch(ctx context.Context, id string) (*tft.Match, error) {
+	path, err := c.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	var m tft.Match
+	if err := readFile(path, &m); err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (c *MatchClient) PutMatch(ctx context.Context, id string, m *tft.Match) error {
+	path, err := c.path(id)
+	if err != nil {
+		return err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return writeFile(path, m)
+}
+
+// path of a match's file. IDs may not escape Dir.
+func (c *MatchClient) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", errors.Errorf("invalid match ID %q", id)
+	}
+
+	return filepath.Join(c.Dir, id+".json"), nil
+}
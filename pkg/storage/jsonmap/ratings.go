@@ -0,0 +1,73 @@
+package jsonmap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/pkg/errors"
+)
+
+var _ leaderboards.RatingStore = &RatingClient{}
+
+// RatingClient persists rating history in a JSON encoded map.
+type RatingClient struct {
+	// Path to the JSON encoded rating map.
+	Path    string
+	History map[string][]leaderboards.RatingChange // Key = Leaderboard ID
+	mux     *sync.Mutex
+}
+
+func NewRatingClient(path string) (*RatingClient, error) {
+	c := &RatingClient{
+		Path:    path,
+		History: make(map[string][]leaderboards.RatingChange),
+		mux:     &sync.Mutex{},
+	}
+
+	ok, err := openFile(path)
+	if err != nil || !ok {
+		return c, err
+	}
+
+	if err := readFile(path, &c.History); err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", path)
+	}
+
+	return c, nil
+}
+
+func (c *RatingClient) GetRatingHistory(ctx context.Context, boardID string) ([]leaderboards.RatingChange, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	history := make([]leaderboards.RatingChange, len(c.History[boardID]))
+	copy(history, c.History[boardID])
+
+	return history, nil
+}
+
+// PutRatingChanges appends changes to a board's history. Changes to a player's
+// rating for a match that's already recorded are ignored, e.g. when the same
+// matches are rated by concurrent updates.
+func (c *RatingClient) PutRatingChanges(ctx context.Context, boardID string, changes []leaderboards.RatingChange) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	recorded := make(map[string]bool) // Key = PUUID and Match ID
+	for _, rc := range c.History[boardID] {
+		recorded[rc.PUUID+"/"+rc.MatchID] = true
+	}
+
+	for _, rc := range changes {
+		key := rc.PUUID + "/" + rc.MatchID
+		if recorded[key] {
+			continue
+		}
+
+		recorded[key] = true
+		c.History[boardID] = append(c.History[boardID], rc)
+	}
+
+	return writeFile(c.Path, &c.History)
+}
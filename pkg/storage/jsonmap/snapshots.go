@@ -0,0 +1,71 @@
+package jsonmap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/leaderboards"
+	"github.com/pkg/errors"
+)
+
+var _ leaderboards.SnapshotStore = &SnapshotClient{}
+
+// SnapshotClient persists league snapshots in a JSON encoded map.
+type SnapshotClient struct {
+	// Path to the JSON encoded snapshot map.
+	Path      string
+	Snapshots map[string][]leaderboards.LeagueSnapshot // Key = Summoner.PUUID
+	mux       *sync.Mutex
+}
+
+func NewSnapshotClient(path string) (*SnapshotClient, error) {
+	c := &SnapshotClient{
+		Path:      path,
+		Snapshots: make(map[string][]leaderboards.LeagueSnapshot),
+		mux:       &sync.Mutex{},
+	}
+
+	ok, err := openFile(path)
+	if err != nil || !ok {
+		return c, err
+	}
+
+	if err := readFile(path, &c.Snapshots); err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", path)
+	}
+
+	return c, nil
+}
+
+func (c *SnapshotClient) PutSnapshots(ctx context.Context, snaps []leaderboards.LeagueSnapshot) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, snap := range snaps {
+		c.Snapshots[snap.PUUID] = append(c.Snapshots[snap.PUUID], snap)
+	}
+
+	return writeFile(c.Path, &c.Snapshots)
+}
+
+// ListSnapshots assumes snapshots were put oldest first.
+func (c *SnapshotClient) ListSnapshots(ctx context.Context, puuid, queueType string, from, to time.Time) ([]leaderboards.LeagueSnapshot, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var snaps []leaderboards.LeagueSnapshot
+	for _, snap := range c.Snapshots[puuid] {
+		if snap.QueueType != queueType {
+			continue
+		}
+
+		if (!from.IsZero() && snap.TakenAt.Before(from)) || (!to.IsZero() && snap.TakenAt.After(to)) {
+			continue
+		}
+
+		snaps = append(snaps, snap)
+	}
+
+	return snaps, nil
+}
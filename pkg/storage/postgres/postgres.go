@@ -2,6 +2,10 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	boards "github.com/alee792/teamfit/pkg/leaderboards"
@@ -10,6 +14,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+var (
+	_ boards.Storage       = &Client{}
+	_ boards.MatchStore    = &Client{}
+	_ boards.RatingStore   = &Client{}
+	_ boards.SnapshotStore = &Client{}
+	_ boards.ClusterStore  = &Client{}
+	_ boards.AwardStore    = &Client{}
+)
+
 type Client struct {
 	DB *sqlx.DB
 }
@@ -18,13 +31,22 @@ func (c *Client) Initialize(ctx context.Context) error {
 	_, err := c.DB.ExecContext(ctx, `
 	CREATE TABLE IF NOT EXISTS leaderboards (
 		id text PRIMARY KEY,
-		name text
+		name text,
+		queues jsonb
 	)
 	`)
 	if err != nil {
 		return errors.Wrap(err, "failed to create leaderboards table")
 	}
 
+	// Boards created before queue defaults lack the column.
+	_, err = c.DB.ExecContext(ctx, `
+	ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS queues jsonb
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to add leaderboards queues")
+	}
+
 	_, err = c.DB.ExecContext(ctx, `
 	CREATE TABLE IF NOT EXISTS summoners (
 		puuid text PRIMARY KEY,
@@ -66,6 +88,7 @@ func (c *Client) Initialize(ctx context.Context) error {
 	CREATE TABLE IF NOT EXISTS leaderboardsMembership (
 		puuid text REFERENCES summoners(puuid),
 		name text,
+		platform text,
 		boardID text REFERENCES leaderboards(id)
 	)
 	`)
@@ -73,20 +96,100 @@ func (c *Client) Initialize(ctx context.Context) error {
 		return errors.Wrap(err, "failed to create summoners table")
 	}
 
+	// Memberships created before Summoners had a Platform lack the column.
+	_, err = c.DB.ExecContext(ctx, `
+	ALTER TABLE leaderboardsMembership ADD COLUMN IF NOT EXISTS platform text
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to add leaderboardsMembership platform")
+	}
+
+	_, err = c.DB.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS matches (
+		id text PRIMARY KEY,
+		data jsonb NOT NULL
+	)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create matches table")
+	}
+
+	_, err = c.DB.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS ratingHistory (
+		boardID text REFERENCES leaderboards(id),
+		puuid text,
+		matchID text,
+		startedAt timestamp,
+		placement int,
+		before double precision,
+		after double precision,
+		PRIMARY KEY (boardID, puuid, matchID)
+	)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create ratingHistory table")
+	}
+
+	_, err = c.DB.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS leagueSnapshots (
+		puuid text,
+		queueType text,
+		tier text,
+		rank text,
+		leaguePoints int,
+		wins int,
+		losses int,
+		takenAt timestamp
+	)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create leagueSnapshots table")
+	}
+
+	_, err = c.DB.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS clusters (
+		boardID text REFERENCES leaderboards(id),
+		patch text,
+		data jsonb NOT NULL,
+		PRIMARY KEY (boardID, patch)
+	)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create clusters table")
+	}
+
+	_, err = c.DB.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS awards (
+		boardID text REFERENCES leaderboards(id),
+		achievementID text,
+		name text,
+		puuid text,
+		matchID text,
+		earnedAt timestamp,
+		value int,
+		PRIMARY KEY (boardID, achievementID, puuid, matchID)
+	)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create awards table")
+	}
+
 	return nil
 }
 
 func (c *Client) CreateLeaderboard(ctx context.Context, board *boards.Leaderboard) (*boards.Leaderboard, error) {
+	queues, err := json.Marshal(board.Queues)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode queues")
+	}
+
 	q := sq.Insert("leaderboards").
-		Columns("id", "name").
-		Values(board.ID, board.Name).
+		Columns("id", "name", "queues").
+		Values(board.ID, board.Name, queues).
 		RunWith(c.DB).
 		PlaceholderFormat(sq.Dollar)
 
-	var out boards.Leaderboard
-
-	err := q.QueryRowContext(ctx).Scan(&out)
-	if err != nil {
+	if _, err := q.ExecContext(ctx); err != nil {
 		return nil, err
 	}
 
@@ -97,14 +200,48 @@ func (c *Client) CreateLeaderboard(ctx context.Context, board *boards.Leaderboar
 		}
 	}
 
-	return &out, nil
+	return c.GetLeaderboard(ctx, board.ID)
 }
 
-func (c *Client) CreateLeaderboardsMembership(ctx context.Context, boardID string, smnr *tft.Summoner) error {
+func (c *Client) CreateLeaderboardsMembership(ctx context.Context, boardID string, smnr *boards.Summoner) error {
+	return c.createMembership(ctx, c.DB, boardID, smnr)
+}
+
+// createMembership runs with a DB or transaction.
+func (c *Client) createMembership(ctx context.Context, runner sq.BaseRunner, boardID string, smnr *boards.Summoner) error {
+	if err := c.putSummoner(ctx, runner, &smnr.Summoner); err != nil {
+		return errors.Wrap(err, "failed to save summoner")
+	}
+
 	q := sq.Insert("leaderboardsMembership").
-		Columns("puiid", "name", "boardID").
-		Values(smnr.PUUID, smnr.Name, boardID).
-		RunWith(c.DB)
+		Columns("puuid", "name", "platform", "boardID").
+		Values(smnr.PUUID, smnr.Name, string(smnr.Platform), boardID).
+		RunWith(runner).
+		PlaceholderFormat(sq.Dollar)
+
+	if _, err := q.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutSummoner inserts or updates a Summoner by PUUID.
+func (c *Client) PutSummoner(ctx context.Context, smnr *tft.Summoner) error {
+	return c.putSummoner(ctx, c.DB, smnr)
+}
+
+func (c *Client) putSummoner(ctx context.Context, runner sq.BaseRunner, smnr *tft.Summoner) error {
+	q := sq.Insert("summoners").
+		Columns("puuid", "id", "name", "accountID", "profileIconID", "summonerLevel", "revisionDate").
+		Values(smnr.PUUID, smnr.ID, smnr.Name, smnr.AccountID, smnr.ProileIconID, smnr.SummonerLevel, boards.UnixMS(smnr.RevisionDate)).
+		Suffix(`ON CONFLICT (puuid) DO UPDATE SET
+			name = EXCLUDED.name,
+			profileIconID = EXCLUDED.profileIconID,
+			summonerLevel = EXCLUDED.summonerLevel,
+			revisionDate = EXCLUDED.revisionDate`).
+		RunWith(runner).
+		PlaceholderFormat(sq.Dollar)
 
 	if _, err := q.ExecContext(ctx); err != nil {
 		return err
@@ -114,16 +251,419 @@ func (c *Client) CreateLeaderboardsMembership(ctx context.Context, boardID strin
 }
 
 func (c *Client) GetLeaderboard(ctx context.Context, id string) (*boards.Leaderboard, error) {
-	q := sq.Select().
+	q := sq.Select("id", "name", "queues").
 		From("leaderboards").
-		Join("leaderboardsMembership ON leaderboards.ID = leaderboardsMembership.boardID").
-		Where(sq.Eq{"boardID": id}).
-		RunWith(c.DB)
+		Where(sq.Eq{"id": id}).
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	var (
+		out    boards.Leaderboard
+		queues []byte
+	)
+
+	err := q.QueryRowContext(ctx).Scan(&out.ID, &out.Name, &queues)
+	if err == sql.ErrNoRows {
+		return nil, errors.Wrapf(boards.ErrNotFound, "leaderboard %s", id)
+	}
 
-	var out *boards.Leaderboard
-	if err := q.QueryRowContext(ctx).Scan(&out); err != nil {
+	if err != nil {
 		return nil, err
 	}
 
+	if len(queues) > 0 {
+		if err := json.Unmarshal(queues, &out.Queues); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode leaderboard %s queues", id)
+		}
+	}
+
+	rows, err := sq.Select("m.puuid", "m.name", "COALESCE(m.platform, '')", "s.id", "s.accountID", "s.profileIconID", "s.summonerLevel").
+		From("leaderboardsMembership m").
+		Join("summoners s ON s.puuid = m.puuid").
+		Where(sq.Eq{"m.boardID": id}).
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get leaderboard membership")
+	}
+	defer rows.Close()
+
+	out.Summoners = make(map[string]boards.Summoner)
+	for rows.Next() {
+		var (
+			smnr     boards.Summoner
+			platform string
+		)
+
+		if err := rows.Scan(&smnr.PUUID, &smnr.Name, &platform, &smnr.ID, &smnr.AccountID, &smnr.ProileIconID, &smnr.SummonerLevel); err != nil {
+			return nil, err
+		}
+
+		smnr.Platform = tft.Platform(platform)
+		out.Summoners[smnr.PUUID] = smnr
+	}
+
+	return &out, rows.Err()
+}
+
+func (c *Client) ListLeaderboards(ctx context.Context) ([]*boards.Leaderboard, error) {
+	rows, err := sq.Select("id").
+		From("leaderboards").
+		OrderBy("id").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list leaderboards")
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []*boards.Leaderboard
+	for _, id := range ids {
+		board, err := c.GetLeaderboard(ctx, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get leaderboard %s", id)
+		}
+
+		out = append(out, board)
+	}
+
 	return out, nil
 }
+
+func (c *Client) UpdateLeaderboard(ctx context.Context, id string, board *boards.Leaderboard) (*boards.Leaderboard, error) {
+	tx, err := c.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	queues, err := json.Marshal(board.Queues)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode queues")
+	}
+
+	_, err = sq.Update("leaderboards").
+		Set("name", board.Name).
+		Set("queues", queues).
+		Where(sq.Eq{"id": id}).
+		RunWith(tx).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update leaderboard")
+	}
+
+	_, err = sq.Delete("leaderboardsMembership").
+		Where(sq.Eq{"boardID": id}).
+		RunWith(tx).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to clear leaderboard membership")
+	}
+
+	for _, smnr := range board.Summoners {
+		smnr := smnr
+		if err := c.createMembership(ctx, tx, id, &smnr); err != nil {
+			return nil, errors.Wrap(err, "failed to create leaderboard membership")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return c.GetLeaderboard(ctx, id)
+}
+
+func (c *Client) GetMatch(ctx context.Context, id string) (*tft.Match, error) {
+	q := sq.Select("data").
+		From("matches").
+		Where(sq.Eq{"id": id}).
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	var data []byte
+	err := q.QueryRowContext(ctx).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m tft.Match
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode match %s", id)
+	}
+
+	return &m, nil
+}
+
+func (c *Client) PutMatch(ctx context.Context, id string, m *tft.Match) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode match %s", id)
+	}
+
+	// Matches are immutable, so the first write wins.
+	q := sq.Insert("matches").
+		Columns("id", "data").
+		Values(id, data).
+		Suffix("ON CONFLICT (id) DO NOTHING").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	if _, err := q.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetRatingHistory(ctx context.Context, boardID string) ([]boards.RatingChange, error) {
+	rows, err := sq.Select("matchID", "puuid", "startedAt", "placement", "before", "after").
+		From("ratingHistory").
+		Where(sq.Eq{"boardID": boardID}).
+		OrderBy("startedAt", "matchID", "placement").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get rating history")
+	}
+	defer rows.Close()
+
+	var history []boards.RatingChange
+	for rows.Next() {
+		var rc boards.RatingChange
+		if err := rows.Scan(&rc.MatchID, &rc.PUUID, &rc.StartedAt, &rc.Placement, &rc.Before, &rc.After); err != nil {
+			return nil, err
+		}
+
+		rc.Delta = rc.After - rc.Before
+		history = append(history, rc)
+	}
+
+	return history, rows.Err()
+}
+
+func (c *Client) PutRatingChanges(ctx context.Context, boardID string, changes []boards.RatingChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	q := sq.Insert("ratingHistory").
+		Columns("boardID", "puuid", "matchID", "startedAt", "placement", "before", "after").
+		Suffix("ON CONFLICT (boardID, puuid, matchID) DO NOTHING").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	for _, rc := range changes {
+		q = q.Values(boardID, rc.PUUID, rc.MatchID, rc.StartedAt, rc.Placement, rc.Before, rc.After)
+	}
+
+	if _, err := q.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) PutSnapshots(ctx context.Context, snaps []boards.LeagueSnapshot) error {
+	if len(snaps) == 0 {
+		return nil
+	}
+
+	q := sq.Insert("leagueSnapshots").
+		Columns("puuid", "queueType", "tier", "rank", "leaguePoints", "wins", "losses", "takenAt").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	for _, snap := range snaps {
+		q = q.Values(snap.PUUID, snap.QueueType, snap.Tier, snap.Rank, snap.LeaguePoints, snap.Wins, snap.Losses, snap.TakenAt)
+	}
+
+	if _, err := q.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) ListSnapshots(ctx context.Context, puuid, queueType string, from, to time.Time) ([]boards.LeagueSnapshot, error) {
+	where := sq.And{
+		sq.Eq{"puuid": puuid, "queueType": queueType},
+	}
+
+	if !from.IsZero() {
+		where = append(where, sq.GtOrEq{"takenAt": from})
+	}
+
+	if !to.IsZero() {
+		where = append(where, sq.LtOrEq{"takenAt": to})
+	}
+
+	rows, err := sq.Select("puuid", "queueType", "tier", "rank", "leaguePoints", "wins", "losses", "takenAt").
+		From("leagueSnapshots").
+		Where(where).
+		OrderBy("takenAt").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list snapshots")
+	}
+	defer rows.Close()
+
+	var snaps []boards.LeagueSnapshot
+	for rows.Next() {
+		var snap boards.LeagueSnapshot
+		if err := rows.Scan(&snap.PUUID, &snap.QueueType, &snap.Tier, &snap.Rank, &snap.LeaguePoints, &snap.Wins, &snap.Losses, &snap.TakenAt); err != nil {
+			return nil, err
+		}
+
+		snaps = append(snaps, snap)
+	}
+
+	return snaps, rows.Err()
+}
+
+func (c *Client) PutClusters(ctx context.Context, boardID string, clusters []boards.PatchClusters) error {
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	q := sq.Insert("clusters").
+		Columns("boardID", "patch", "data").
+		Suffix("ON CONFLICT (boardID, patch) DO UPDATE SET data = EXCLUDED.data").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	for _, pc := range clusters {
+		data, err := json.Marshal(pc)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode patch %s clusters", pc.Patch)
+		}
+
+		q = q.Values(boardID, pc.Patch, data)
+	}
+
+	if _, err := q.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) ListClusters(ctx context.Context, boardID string) ([]boards.PatchClusters, error) {
+	rows, err := sq.Select("data").
+		From("clusters").
+		Where(sq.Eq{"boardID": boardID}).
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list clusters")
+	}
+	defer rows.Close()
+
+	var clusters []boards.PatchClusters
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var pc boards.PatchClusters
+		if err := json.Unmarshal(data, &pc); err != nil {
+			return nil, errors.Wrap(err, "failed to decode clusters")
+		}
+
+		clusters = append(clusters, pc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Patches don't sort lexically, e.g. 9.22 < 10.1.
+	sort.Slice(clusters, func(i, j int) bool {
+		return tft.ComparePatches(clusters[i].Patch, clusters[j].Patch) > 0
+	})
+
+	return clusters, nil
+}
+
+func (c *Client) GetAwards(ctx context.Context, boardID string) ([]boards.Award, error) {
+	return c.listAwards(ctx, sq.Eq{"boardID": boardID})
+}
+
+func (c *Client) ListAwards(ctx context.Context, puuid string) ([]boards.Award, error) {
+	return c.listAwards(ctx, sq.Eq{"puuid": puuid})
+}
+
+func (c *Client) listAwards(ctx context.Context, where sq.Eq) ([]boards.Award, error) {
+	rows, err := sq.Select("boardID", "achievementID", "name", "puuid", "matchID", "earnedAt", "value").
+		From("awards").
+		Where(where).
+		OrderBy("earnedAt", "achievementID").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list awards")
+	}
+	defer rows.Close()
+
+	var awards []boards.Award
+	for rows.Next() {
+		var aw boards.Award
+		if err := rows.Scan(&aw.BoardID, &aw.AchievementID, &aw.Name, &aw.PUUID, &aw.MatchID, &aw.EarnedAt, &aw.Value); err != nil {
+			return nil, err
+		}
+
+		awards = append(awards, aw)
+	}
+
+	return awards, rows.Err()
+}
+
+func (c *Client) PutAwards(ctx context.Context, boardID string, awards []boards.Award) error {
+	if len(awards) == 0 {
+		return nil
+	}
+
+	q := sq.Insert("awards").
+		Columns("boardID", "achievementID", "name", "puuid", "matchID", "earnedAt", "value").
+		Suffix("ON CONFLICT (boardID, achievementID, puuid, matchID) DO NOTHING").
+		RunWith(c.DB).
+		PlaceholderFormat(sq.Dollar)
+
+	for _, aw := range awards {
+		q = q.Values(boardID, aw.AchievementID, aw.Name, aw.PUUID, aw.MatchID, aw.EarnedAt, aw.Value)
+	}
+
+	if _, err := q.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
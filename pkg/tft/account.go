@@ -0,0 +1,64 @@
+package tft
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Account is a Riot account, identified across games by a Riot ID,
+// i.e. gameName#tagLine.
+type Account struct {
+	PUUID    string `json:"puuid"`
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+}
+
+// RiotID formats the Account's gameName#tagLine.
+func (a *Account) RiotID() string {
+	return a.GameName + "#" + a.TagLine
+}
+
+// ParseRiotID splits a gameName#tagLine Riot ID.
+func ParseRiotID(riotID string) (gameName, tagLine string, ok bool) {
+	i := strings.LastIndex(riotID, "#")
+	if i < 1 || i == len(riotID)-1 {
+		return "", "", false
+	}
+
+	return riotID[:i], riotID[i+1:], true
+}
+
+// GetAccountByRiotID from account-v1.
+func (c *Client) GetAccountByRiotID(ctx context.Context, gameName, tagLine string) (*Account, error) {
+	e := c.accountEndpoint(ctx, "account-v1.getByRiotId", "/riot/account/v1/accounts/by-riot-id/%s/%s", url.PathEscape(gameName), url.PathEscape(tagLine))
+
+	var a Account
+	if err := c.get(ctx, e, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// GetAccountByPUUID from account-v1.
+func (c *Client) GetAccountByPUUID(ctx context.Context, puuid string) (*Account, error) {
+	e := c.accountEndpoint(ctx, "account-v1.getByPuuid", "/riot/account/v1/accounts/by-puuid/%s", url.PathEscape(puuid))
+
+	var a Account
+	if err := c.get(ctx, e, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// accountEndpoint for account-v1, which is not served by the SEA cluster.
+func (c *Client) accountEndpoint(ctx context.Context, method, format string, a ...interface{}) endpoint {
+	e := c.regionEndpoint(ctx, method, format, a...)
+	if e.route == string(RegionSEA) {
+		e.route = string(RegionAsia)
+	}
+
+	return e
+}
@@ -0,0 +1,118 @@
+package tft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errors reported by the Riot API.
+// Match an *APIError against them with errors.Is.
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrServiceUnavailable = errors.New("service unavailable")
+	// ErrRateLimited is also returned when a request cannot be made
+	// within the rate limit before its context's deadline.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Status is the body of a Riot API error response.
+type Status struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+// APIError is a non-2XX response from the Riot API.
+type APIError struct {
+	StatusCode int
+	// Endpoint is the Riot method that failed, e.g. summoner-v1.getByName.
+	Endpoint string
+	Status   Status
+	// RetryAfter is set when rate limited.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := e.Status.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: %d %s (retry after %s)", e.Endpoint, e.StatusCode, msg, e.RetryAfter)
+	}
+
+	return fmt.Sprintf("%s: %d %s", e.Endpoint, e.StatusCode, msg)
+}
+
+// Is matches an APIError to the sentinel error for its status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServiceUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// newAPIError from a non-2XX response.
+func newAPIError(endpoint string, resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		e.RetryAfter = RetryAfter(resp)
+	}
+
+	// Riot wraps errors as {"status": {"message": "...", "status_code": 404}}.
+	var body struct {
+		Status Status `json:"status"`
+	}
+
+	bb, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err == nil && json.Unmarshal(bb, &body) == nil {
+		e.Status = body.Status
+	}
+
+	return e
+}
+
+// BatchError reports the items of a batch call that failed.
+// Items that succeeded are still returned alongside it.
+type BatchError struct {
+	Errors map[string]error // Key = item, e.g. a summoner name or match ID
+}
+
+func (e *BatchError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", k, e.Errors[k]))
+	}
+
+	return fmt.Sprintf("%d failed: %s", len(keys), strings.Join(msgs, "; "))
+}
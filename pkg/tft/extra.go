@@ -0,0 +1,125 @@
+package tft
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Extra holds JSON fields a type does not model, keyed by their JSON name.
+// It also holds optional fields that were sent as zero values, which
+// omitempty would otherwise drop when re-encoding.
+type Extra map[string]json.RawMessage
+
+// knownFields caches the lower cased JSON names of a struct type's fields,
+// reporting whether each is omitempty.
+var knownFields sync.Map // map[reflect.Type]map[string]bool
+
+// unmarshalExtra decodes data into v, a pointer to a struct without
+// JSON methods, and keeps any fields v does not model in extra.
+func unmarshalExtra(data []byte, v interface{}, extra *Extra) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// encoding/json matches field names case insensitively.
+	known := fieldsOf(reflect.TypeOf(v).Elem())
+	for k, val := range raw {
+		omitempty, ok := known[strings.ToLower(k)]
+		if ok && !(omitempty && isZeroJSON(val)) {
+			delete(raw, k)
+		}
+	}
+
+	*extra = nil
+	if len(raw) > 0 {
+		*extra = raw
+	}
+
+	return nil
+}
+
+// marshalExtra encodes v, a struct without JSON methods, alongside extra.
+// Modeled fields take precedence over extra fields of the same name.
+func marshalExtra(v interface{}, extra Extra) ([]byte, error) {
+	bb, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return bb, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bb, &fields); err != nil {
+		return nil, err
+	}
+
+	for k, raw := range extra {
+		if _, ok := fields[k]; !ok {
+			fields[k] = raw
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+func fieldsOf(t reflect.Type) map[string]bool {
+	if known, ok := knownFields.Load(t); ok {
+		return known.(map[string]bool)
+	}
+
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		opts := strings.Split(f.Tag.Get("json"), ",")
+		name := opts[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		var omitempty bool
+		for _, opt := range opts[1:] {
+			omitempty = omitempty || opt == "omitempty"
+		}
+
+		known[strings.ToLower(name)] = omitempty
+	}
+
+	knownFields.Store(t, known)
+
+	return known
+}
+
+// isZeroJSON reports whether raw is a value omitempty drops,
+// e.g. false, 0, "", null, [] or {}.
+func isZeroJSON(raw json.RawMessage) bool {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return false
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
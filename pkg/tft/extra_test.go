@@ -0,0 +1,55 @@
+package tft
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParticipantRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "set 2",
+			in:   `{"placement":6,"level":8,"last_round":31,"time_eliminated":1800.5,"companion":{"skin_ID":1,"content_ID":"c","species":"PetTFTAvatar"},"traits":[],"players_eliminated":0,"puuid":"p","total_damage_to_players":86,"units":[],"gold_left":0}`,
+		},
+		{
+			name: "zero optional fields",
+			in:   `{"placement":5,"level":8,"last_round":31,"time_eliminated":1800.5,"companion":{"skin_ID":1,"content_ID":"c","item_ID":0,"species":"PetTFTAvatar"},"traits":[{"name":"Set9_Void","num_units":1,"style":0,"tier_current":0,"tier_total":3}],"players_eliminated":0,"puuid":"p","total_damage_to_players":40,"units":[{"tier":1,"items":[],"itemNames":[],"character_id":"TFT9_Malzahar","name":"","rarity":1}],"gold_left":0,"augments":[],"partner_group_id":0,"missions":{},"win":false,"riotIdGameName":"","riotIdTagline":""}`,
+		},
+		{
+			name: "unmodeled fields",
+			in:   `{"placement":1,"level":9,"last_round":38,"time_eliminated":2200,"companion":{"skin_ID":1,"content_ID":"c","species":"PetTFTAvatar"},"traits":[],"players_eliminated":5,"puuid":"p","total_damage_to_players":187,"units":[],"gold_left":5,"win":true,"skill_tree":{"node":0}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var p Participant
+			if err := json.Unmarshal([]byte(tt.in), &p); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := json.Marshal(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want, got interface{}
+			if err := json.Unmarshal([]byte(tt.in), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("re-encoded\n%s\nwant\n%s", out, tt.in)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package tft
+
+import "context"
+
+// DefaultPageSize of match IDs requested by a MatchIterator.
+const DefaultPageSize = 20
+
+// MatchLister lists a player's match IDs, e.g. a Client.
+type MatchLister interface {
+	ListMatches(ctx context.Context, in *ListMatchesRequest) (*ListMatchesResponse, error)
+}
+
+// MatchIterator walks a player's match history page by page, newest first.
+//
+//	it := NewMatchIterator(client, ListMatchesRequest{PUUID: puuid}, 0)
+//	for it.Next(ctx) {
+//		id := it.MatchID()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type MatchIterator struct {
+	lister MatchLister
+	req    ListMatchesRequest
+	limit  int
+	page   []string
+	cur    string
+	seen   int
+	done   bool
+	err    error
+}
+
+// NewMatchIterator iterates over up to limit match IDs matching in.
+// A limit < 1 walks the entire history. in.Count sets the page size.
+func NewMatchIterator(lister MatchLister, in ListMatchesRequest, limit int) *MatchIterator {
+	if in.Count < 1 {
+		in.Count = DefaultPageSize
+	}
+
+	return &MatchIterator{
+		lister: lister,
+		req:    in,
+		limit:  limit,
+	}
+}
+
+// Next advances to the next match ID, fetching pages as needed.
+// It returns false when the history is exhausted or on error.
+func (it *MatchIterator) Next(ctx context.Context) bool {
+	if it.err != nil || (it.limit > 0 && it.seen >= it.limit) {
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+
+		in := it.req
+		if remaining := it.limit - it.seen; it.limit > 0 && remaining < in.Count {
+			in.Count = remaining
+		}
+
+		out, err := it.lister.ListMatches(ctx, &in)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		// A short page is the last page.
+		it.done = len(out.MatchIDs) < in.Count
+		it.page = out.MatchIDs
+		it.req.Start += len(out.MatchIDs)
+
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.cur, it.page = it.page[0], it.page[1:]
+	it.seen++
+
+	return true
+}
+
+// MatchID at the iterator's current position.
+func (it *MatchIterator) MatchID() string {
+	return it.cur
+}
+
+// Err returns the first error encountered while listing matches.
+func (it *MatchIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,147 @@
+package tft_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/alee792/teamfit/pkg/tft/tfttest"
+)
+
+const puuidOne = "54P482v2PkgW1Zg41rv7t7dSS2W_Fxj-2GrDH5gs8fHLgMMxSirgmyijTMfw0vnxYKu3IFIgZ1dMSg"
+
+// historyServer serves a history of n copies of the fixture match, an hour
+// apart, with the newest at newest. Close it when finished.
+func historyServer(t *testing.T, n int, newest time.Time) *tfttest.Server {
+	t.Helper()
+
+	f, err := tfttest.LoadFixtures("tfttest/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := f.Matches[0]
+	f.Matches = nil
+	for i := 0; i < n; i++ {
+		cp := m
+		cp.Metadata.MatchID = fmt.Sprintf("NA1_%04d", n-i)
+		cp.Info.GameTimestamp = int(newest.Add(-time.Duration(i)*time.Hour).UnixNano() / int64(time.Millisecond))
+		f.Matches = append(f.Matches, cp)
+	}
+
+	return tfttest.NewServer(f)
+}
+
+func TestMatchIterator(t *testing.T) {
+	newest := time.Date(2019, 11, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		history  int
+		in       tft.ListMatchesRequest
+		limit    int
+		want     int // Match IDs
+		requests int
+	}{
+		{name: "one page", history: 5, want: 5, requests: 1},
+		{name: "empty history", history: 0, want: 0, requests: 1},
+		{name: "short last page", history: 45, want: 45, requests: 3},
+		{name: "empty last page", history: 40, want: 40, requests: 3},
+		{name: "page size", history: 10, in: tft.ListMatchesRequest{Count: 3}, want: 10, requests: 4},
+		{name: "limit within a page", history: 45, limit: 5, want: 5, requests: 1},
+		{name: "limit across pages", history: 45, limit: 25, want: 25, requests: 2},
+		{name: "limit at a page boundary", history: 45, limit: 20, want: 20, requests: 1},
+		{name: "limit past the history", history: 10, limit: 25, want: 10, requests: 1},
+		{
+			name:     "start time",
+			history:  30,
+			in:       tft.ListMatchesRequest{StartTime: newest.Add(-24*time.Hour + time.Minute)},
+			want:     24,
+			requests: 2,
+		},
+		{
+			name:     "time range",
+			history:  30,
+			in:       tft.ListMatchesRequest{StartTime: newest.Add(-5*time.Hour - time.Minute), EndTime: newest.Add(-2*time.Hour + time.Minute)},
+			want:     4,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := historyServer(t, tt.history, newest)
+			defer srv.Close()
+
+			in := tt.in
+			in.PUUID = puuidOne
+
+			var (
+				it  = tft.NewMatchIterator(srv.APIClient(), in, tt.limit)
+				ids []string
+			)
+
+			for it.Next(context.Background()) {
+				ids = append(ids, it.MatchID())
+			}
+
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(ids) != tt.want {
+				t.Errorf("got %d match IDs, want %d", len(ids), tt.want)
+			}
+
+			// Newest first without repeats.
+			seen := make(map[string]bool)
+			for i, id := range ids {
+				if seen[id] {
+					t.Errorf("%s listed twice", id)
+				}
+
+				seen[id] = true
+				if i > 0 && id > ids[i-1] {
+					t.Errorf("%s listed after older %s", id, ids[i-1])
+				}
+			}
+
+			if n := srv.Requests(); n != tt.requests {
+				t.Errorf("made %d requests, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+// failingLister fails every request, counting them.
+type failingLister struct {
+	calls int
+}
+
+func (l *failingLister) ListMatches(ctx context.Context, in *tft.ListMatchesRequest) (*tft.ListMatchesResponse, error) {
+	l.calls++
+	return nil, errors.New("unavailable")
+}
+
+func TestMatchIteratorError(t *testing.T) {
+	var (
+		l  = &failingLister{}
+		it = tft.NewMatchIterator(l, tft.ListMatchesRequest{PUUID: puuidOne}, 0)
+	)
+
+	if it.Next(context.Background()) {
+		t.Fatal("Next succeeded after an error")
+	}
+
+	if err := it.Err(); err == nil {
+		t.Error("expected an error")
+	}
+
+	// Errors stop the iterator.
+	if it.Next(context.Background()) || l.calls != 1 {
+		t.Errorf("made %d requests, want 1", l.calls)
+	}
+}
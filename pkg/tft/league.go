@@ -0,0 +1,169 @@
+package tft
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Ranked queue types reported by league-v1.
+const (
+	QueueTypeRanked         = "RANKED_TFT"
+	QueueTypeRankedTurbo    = "RANKED_TFT_TURBO"
+	QueueTypeRankedDoubleUp = "RANKED_TFT_DOUBLE_UP"
+)
+
+// Tiers of the ranked ladder, lowest first.
+const (
+	TierIron        = "IRON"
+	TierBronze      = "BRONZE"
+	TierSilver      = "SILVER"
+	TierGold        = "GOLD"
+	TierPlatinum    = "PLATINUM"
+	TierEmerald     = "EMERALD"
+	TierDiamond     = "DIAMOND"
+	TierMaster      = "MASTER"
+	TierGrandmaster = "GRANDMASTER"
+	TierChallenger  = "CHALLENGER"
+)
+
+// Divisions of the tiers below Master.
+const (
+	DivisionI   = "I"
+	DivisionII  = "II"
+	DivisionIII = "III"
+	DivisionIV  = "IV"
+)
+
+// LeagueList is a ranked league, e.g. the Challenger league.
+type LeagueList struct {
+	LeagueID string       `json:"leagueId"`
+	Entries  []LeagueItem `json:"entries"`
+	Tier     string       `json:"tier"`
+	Name     string       `json:"name"`
+	Queue    string       `json:"queue"`
+}
+
+// LeagueItem is a player's standing within a LeagueList.
+type LeagueItem struct {
+	FreshBlood   bool       `json:"freshBlood"`
+	Wins         int        `json:"wins"`
+	SummonerName string     `json:"summonerName"`
+	MiniSeries   MiniSeries `json:"miniSeries,omitempty"`
+	Inactive     bool       `json:"inactive"`
+	Veteran      bool       `json:"veteran"`
+	HotStreak    bool       `json:"hotStreak"`
+	Rank         string     `json:"rank"`
+	LeaguePoints int        `json:"leaguePoints"`
+	Losses       int        `json:"losses"`
+	SummonerID   string     `json:"summonerId"`
+	PUUID        string     `json:"puuid,omitempty"`
+}
+
+// TopRatedLadderEntry is a player's standing in a rated queue, e.g. Hyper Roll.
+type TopRatedLadderEntry struct {
+	SummonerID                   string `json:"summonerId"`
+	SummonerName                 string `json:"summonerName"`
+	PUUID                        string `json:"puuid,omitempty"`
+	RatedTier                    string `json:"ratedTier"`
+	RatedRating                  int    `json:"ratedRating"`
+	Wins                         int    `json:"wins"`
+	PreviousUpdateLadderPosition int    `json:"previousUpdateLadderPosition"`
+}
+
+func (c *Client) GetChallengerLeague(ctx context.Context) (*LeagueList, error) {
+	return c.getLeagueList(ctx, c.platformEndpoint(ctx, "league-v1.getChallengerLeague", "/tft/league/v1/challenger"))
+}
+
+func (c *Client) GetGrandmasterLeague(ctx context.Context) (*LeagueList, error) {
+	return c.getLeagueList(ctx, c.platformEndpoint(ctx, "league-v1.getGrandmasterLeague", "/tft/league/v1/grandmaster"))
+}
+
+func (c *Client) GetMasterLeague(ctx context.Context) (*LeagueList, error) {
+	return c.getLeagueList(ctx, c.platformEndpoint(ctx, "league-v1.getMasterLeague", "/tft/league/v1/master"))
+}
+
+// GetApexLeague for Master, Grandmaster or Challenger.
+func (c *Client) GetApexLeague(ctx context.Context, tier string) (*LeagueList, error) {
+	switch strings.ToUpper(tier) {
+	case TierChallenger:
+		return c.GetChallengerLeague(ctx)
+	case TierGrandmaster:
+		return c.GetGrandmasterLeague(ctx)
+	case TierMaster:
+		return c.GetMasterLeague(ctx)
+	}
+
+	return nil, fmt.Errorf("not an apex tier: %q", tier)
+}
+
+// IsApexTier reports whether a tier, in any case, has a single league,
+// i.e. master, grandmaster or challenger.
+func IsApexTier(tier string) bool {
+	switch strings.ToUpper(tier) {
+	case TierChallenger, TierGrandmaster, TierMaster:
+		return true
+	}
+
+	return false
+}
+
+func (c *Client) GetLeague(ctx context.Context, leagueID string) (*LeagueList, error) {
+	return c.getLeagueList(ctx, c.platformEndpoint(ctx, "league-v1.getLeagueById", "/tft/league/v1/leagues/%s", url.PathEscape(leagueID)))
+}
+
+func (c *Client) getLeagueList(ctx context.Context, e endpoint) (*LeagueList, error) {
+	var l LeagueList
+	if err := c.get(ctx, e, &l); err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+type ListLeagueEntriesRequest struct {
+	Tier     string
+	Division string
+	// Page starts at 1.
+	Page int
+}
+
+type ListLeagueEntriesResponse struct {
+	Entries []LeagueEntry
+}
+
+// ListLeagueEntries in a tier and division below Master, one page at a time.
+// An empty page marks the end of the division.
+func (c *Client) ListLeagueEntries(ctx context.Context, in *ListLeagueEntriesRequest) (*ListLeagueEntriesResponse, error) {
+	if in.Tier == "" || in.Division == "" {
+		return nil, fmt.Errorf("invalid tier or division")
+	}
+
+	e := c.platformEndpoint(ctx, "league-v1.getLeagueEntries", "/tft/league/v1/entries/%s/%s", strings.ToUpper(in.Tier), strings.ToUpper(in.Division))
+	if in.Page > 1 {
+		e.query = url.Values{"page": []string{strconv.Itoa(in.Page)}}
+	}
+
+	var out []LeagueEntry
+	if err := c.get(ctx, e, &out); err != nil {
+		return nil, err
+	}
+
+	return &ListLeagueEntriesResponse{
+		Entries: out,
+	}, nil
+}
+
+// GetRatedLadder of a rated queue's top players, e.g. QueueTypeRankedTurbo for Hyper Roll.
+func (c *Client) GetRatedLadder(ctx context.Context, queueType string) ([]TopRatedLadderEntry, error) {
+	e := c.platformEndpoint(ctx, "league-v1.getTopRatedLadder", "/tft/league/v1/rated-ladders/%s/top", url.PathEscape(queueType))
+
+	var out []TopRatedLadderEntry
+	if err := c.get(ctx, e, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
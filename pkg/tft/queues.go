@@ -0,0 +1,92 @@
+package tft
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Queue IDs of TFT matches, i.e. Info.QueueID.
+const (
+	QueueNormal         = 1090
+	QueueRanked         = 1100
+	QueueTutorial       = 1110
+	QueueHyperRoll      = 1130
+	QueueDoubleUpBeta   = 1150
+	QueueDoubleUp       = 1160
+	QueueFortunesFavor  = 1170
+	QueueSoulBrawl      = 1180
+	QueueChonccTreasure = 1190
+	QueueRevival        = 6000
+)
+
+// queueNames describe each queue.
+var queueNames = map[int]string{
+	QueueNormal:         "Normal",
+	QueueRanked:         "Ranked",
+	QueueTutorial:       "Tutorial",
+	QueueHyperRoll:      "Hyper Roll",
+	QueueDoubleUpBeta:   "Double Up (Workshop)",
+	QueueDoubleUp:       "Double Up",
+	QueueFortunesFavor:  "Fortune's Favor",
+	QueueSoulBrawl:      "Soul Brawl",
+	QueueChonccTreasure: "Choncc's Treasure",
+	QueueRevival:        "Set Revival",
+}
+
+// Modes group queues by name, e.g. "doubleup".
+var Modes = map[string][]int{
+	"normal":    {QueueNormal},
+	"ranked":    {QueueRanked},
+	"tutorial":  {QueueTutorial},
+	"hyperroll": {QueueHyperRoll},
+	"doubleup":  {QueueDoubleUpBeta, QueueDoubleUp},
+	"event":     {QueueFortunesFavor, QueueSoulBrawl, QueueChonccTreasure},
+	"revival":   {QueueRevival},
+}
+
+// QueueName describes a queue ID, e.g. "Hyper Roll".
+func QueueName(id int) string {
+	if name, ok := queueNames[id]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Queue %d", id)
+}
+
+// ParseQueues parses a comma separated list of modes or queue IDs,
+// e.g. "ranked,1160". Queues are sorted and unique.
+func ParseQueues(s string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.ToLower(strings.TrimSpace(raw))
+		if raw == "" {
+			continue
+		}
+
+		if ids, ok := Modes[strings.NewReplacer(" ", "", "-", "", "_", "").Replace(raw)]; ok {
+			for _, id := range ids {
+				seen[id] = true
+			}
+
+			continue
+		}
+
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("unknown queue: %q", raw)
+		}
+
+		seen[id] = true
+	}
+
+	var ids []int
+	for id := range seen {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,314 @@
+package tft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAppRateLimit matches the limits of a Riot development key.
+// Limits are replaced by the X-App-Rate-Limit header after the first response.
+const DefaultAppRateLimit = "20:1,100:120"
+
+// defaultRetryAfter blocks a route when a 429 omits Retry-After.
+const defaultRetryAfter = time.Second
+
+// Rate limit headers set by the Riot API.
+const (
+	headerAppRateLimit         = "X-App-Rate-Limit"
+	headerAppRateLimitCount    = "X-App-Rate-Limit-Count"
+	headerMethodRateLimit      = "X-Method-Rate-Limit"
+	headerMethodRateLimitCount = "X-Method-Rate-Limit-Count"
+	headerRateLimitType        = "X-Rate-Limit-Type"
+	headerRetryAfter           = "Retry-After"
+)
+
+// Limiter schedules requests within Riot's app and method rate limits.
+// App limits are tracked per route, i.e. Platform or Region, and method limits
+// per route and method. Calls to the same route wait in line for each other.
+type Limiter struct {
+	mux      sync.Mutex
+	appLimit []window
+	buckets  map[string]*bucket // Key = route or route + method
+	queues   map[string]chan struct{}
+	now      func() time.Time
+}
+
+// bucket of rate limit windows that share a key.
+type bucket struct {
+	windows      []*window
+	blockedUntil time.Time
+}
+
+// window is a fixed rate limit window that starts with its first request.
+type window struct {
+	max    int
+	period time.Duration
+	count  int
+	reset  time.Time
+}
+
+// NewLimiter seeds every route's app limit with a Riot formatted
+// rate limit, e.g. "20:1,100:120". An empty limit is unlimited
+// until the API responds with its limits.
+func NewLimiter(appLimit string) (*Limiter, error) {
+	ww, err := parseRateLimit(appLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		appLimit: ww,
+		buckets:  make(map[string]*bucket),
+		queues:   make(map[string]chan struct{}),
+		now:      time.Now,
+	}, nil
+}
+
+// Wait blocks until a request to a route's method is within its rate limits.
+// If the wait would exceed ctx's deadline, Wait fails fast with an
+// *APIError matching ErrRateLimited.
+func (l *Limiter) Wait(ctx context.Context, route, method string) error {
+	q := l.queue(route)
+
+	select {
+	case q <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-q }()
+
+	for {
+		wait := l.reserve(route, method)
+		if wait <= 0 {
+			return nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && l.now().Add(wait).After(deadline) {
+			return &APIError{
+				StatusCode: http.StatusTooManyRequests,
+				Endpoint:   method,
+				Status: Status{
+					Message:    fmt.Sprintf("%s rate limit exceeds deadline", route),
+					StatusCode: http.StatusTooManyRequests,
+				},
+				RetryAfter: wait,
+			}
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Update synchronizes a route's limits with a response's headers.
+// A 429 blocks the offending limit until Retry-After has elapsed.
+func (l *Limiter) Update(route, method string, resp *http.Response) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+	app := l.bucket(route, "")
+	meth := l.bucket(route, method)
+
+	app.sync(now, resp.Header.Get(headerAppRateLimit), resp.Header.Get(headerAppRateLimitCount))
+	meth.sync(now, resp.Header.Get(headerMethodRateLimit), resp.Header.Get(headerMethodRateLimitCount))
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	until := now.Add(RetryAfter(resp))
+	switch resp.Header.Get(headerRateLimitType) {
+	case "application":
+		app.blockedUntil = until
+	default:
+		// Method and service limits only affect the method.
+		meth.blockedUntil = until
+	}
+}
+
+// RetryAfter parses a response's Retry-After header.
+func RetryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get(headerRetryAfter))
+	if err != nil || secs < 0 {
+		return defaultRetryAfter
+	}
+
+	return time.Duration(secs) * time.Second
+}
+
+// reserve a request if possible, otherwise return how long to wait.
+func (l *Limiter) reserve(route, method string) time.Duration {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+	app := l.bucket(route, "")
+	meth := l.bucket(route, method)
+
+	wait := app.wait(now)
+	if w := meth.wait(now); w > wait {
+		wait = w
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	app.take(now)
+	meth.take(now)
+
+	return 0
+}
+
+func (l *Limiter) bucket(route, method string) *bucket {
+	key := route
+	if method != "" {
+		key = route + ":" + method
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{}
+		// Only app limits are known before the first response.
+		if method == "" {
+			for _, w := range l.appLimit {
+				w := w
+				b.windows = append(b.windows, &w)
+			}
+		}
+
+		l.buckets[key] = b
+	}
+
+	return b
+}
+
+func (l *Limiter) queue(route string) chan struct{} {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	q, ok := l.queues[route]
+	if !ok {
+		q = make(chan struct{}, 1)
+		l.queues[route] = q
+	}
+
+	return q
+}
+
+func (b *bucket) wait(now time.Time) time.Duration {
+	var wait time.Duration
+	if now.Before(b.blockedUntil) {
+		wait = b.blockedUntil.Sub(now)
+	}
+
+	for _, w := range b.windows {
+		if w.count < w.max || !now.Before(w.reset) {
+			continue
+		}
+
+		if d := w.reset.Sub(now); d > wait {
+			wait = d
+		}
+	}
+
+	return wait
+}
+
+func (b *bucket) take(now time.Time) {
+	for _, w := range b.windows {
+		if !now.Before(w.reset) {
+			w.count = 0
+			w.reset = now.Add(w.period)
+		}
+
+		w.count++
+	}
+}
+
+// sync replaces a bucket's limits with those reported by the API,
+// preferring the API's count when it has seen more requests than we have.
+func (b *bucket) sync(now time.Time, rawLimit, rawCount string) {
+	if rawLimit == "" {
+		return
+	}
+
+	limits, err := parseRateLimit(rawLimit)
+	if err != nil {
+		return
+	}
+
+	// Counts use the same format as limits, e.g. "1:1,1:120".
+	counts, _ := parseRateLimit(rawCount)
+
+	var windows []*window
+	for _, lim := range limits {
+		lim := lim
+		w := &lim
+		for _, prev := range b.windows {
+			if prev.period == lim.period {
+				w = prev
+				w.max = lim.max
+				break
+			}
+		}
+
+		if !now.Before(w.reset) {
+			w.count = 0
+			w.reset = now.Add(w.period)
+		}
+
+		for _, c := range counts {
+			if c.period == w.period && c.max > w.count {
+				w.count = c.max
+			}
+		}
+
+		windows = append(windows, w)
+	}
+
+	b.windows = windows
+}
+
+// parseRateLimit parses comma separated "requests:seconds" pairs.
+func parseRateLimit(s string) ([]window, error) {
+	var ww []window
+	if strings.TrimSpace(s) == "" {
+		return ww, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid rate limit: %q", s)
+		}
+
+		max, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit: %q", s)
+		}
+
+		secs, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit: %q", s)
+		}
+
+		ww = append(ww, window{
+			max:    max,
+			period: time.Duration(secs) * time.Second,
+		})
+	}
+
+	return ww, nil
+}
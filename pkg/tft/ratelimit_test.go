@@ -0,0 +1,155 @@
+package tft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testMethod = "summoner-v1.getByName"
+
+// rateLimitServer responds with headers and status to the first limited
+// requests, then with 200s, counting requests in hits.
+func rateLimitServer(headers map[string]string, status int, limited int32, hits *int32) (*Client, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(hits, 1)
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+
+		if n <= limited {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"status":{"message":"Rate limit exceeded","status_code":429}}`))
+			return
+		}
+
+		w.Write([]byte(`{"name":"Tactician One"}`))
+	}))
+
+	return NewClient(srv.Client(), Config{
+		APIKey:   "test",
+		Platform: PlatformNA1,
+		BaseURL:  srv.URL,
+	}), srv.Close
+}
+
+func TestLimiterSyncsHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wait    time.Duration
+	}{
+		{
+			name: "under limits",
+			headers: map[string]string{
+				headerAppRateLimit:         "20:1,100:120",
+				headerAppRateLimitCount:    "1:1,1:120",
+				headerMethodRateLimit:      "50:10",
+				headerMethodRateLimitCount: "1:10",
+			},
+		},
+		{
+			name: "app limit exhausted",
+			headers: map[string]string{
+				headerAppRateLimit:         "20:1,100:120",
+				headerAppRateLimitCount:    "1:1,100:120",
+				headerMethodRateLimit:      "50:10",
+				headerMethodRateLimitCount: "1:10",
+			},
+			wait: 120 * time.Second,
+		},
+		{
+			name: "method limit exhausted",
+			headers: map[string]string{
+				headerAppRateLimit:         "20:1,100:120",
+				headerAppRateLimitCount:    "1:1,1:120",
+				headerMethodRateLimit:      "50:10",
+				headerMethodRateLimitCount: "50:10",
+			},
+			wait: 10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			c, done := rateLimitServer(tt.headers, http.StatusOK, 0, &hits)
+			defer done()
+
+			now := time.Date(2019, 11, 11, 0, 0, 0, 0, time.UTC)
+			c.Limiter.now = func() time.Time { return now }
+
+			if _, err := c.GetSummoner(context.Background(), "Tactician One"); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.Limiter.reserve(string(PlatformNA1), testMethod); got != tt.wait {
+				t.Errorf("wait = %s, want %s", got, tt.wait)
+			}
+
+			// Windows reset once their period has elapsed.
+			now = now.Add(tt.wait)
+			if got := c.Limiter.reserve(string(PlatformNA1), testMethod); got != 0 {
+				t.Errorf("wait after reset = %s, want 0", got)
+			}
+		})
+	}
+}
+
+func TestLimiterBlocksOnRetryAfter(t *testing.T) {
+	var hits int32
+	c, done := rateLimitServer(map[string]string{
+		headerRetryAfter:    "1",
+		headerRateLimitType: "method",
+	}, http.StatusTooManyRequests, 1, &hits)
+	defer done()
+
+	start := time.Now()
+	if _, err := c.GetSummoner(context.Background(), "Tactician One"); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least 1s", elapsed)
+	}
+
+	if atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("hits = %d, want 2", hits)
+	}
+}
+
+func TestLimiterFailsFastPastDeadline(t *testing.T) {
+	var hits int32
+	c, done := rateLimitServer(map[string]string{
+		headerRetryAfter:    "30",
+		headerRateLimitType: "application",
+	}, http.StatusTooManyRequests, 1, &hits)
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetSummoner(ctx, "Tactician One")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter <= 0 {
+		t.Errorf("err = %#v, want a RetryAfter", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("failed after %s, want fail fast", elapsed)
+	}
+
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("hits = %d, want 1", hits)
+	}
+}
@@ -0,0 +1,169 @@
+package tft
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Platform routes requests to platform scoped endpoints,
+// e.g. summoner-v1 and league-v1.
+type Platform string
+
+// Platforms supported by the Riot API.
+const (
+	PlatformBR1  Platform = "br1"
+	PlatformEUN1 Platform = "eun1"
+	PlatformEUW1 Platform = "euw1"
+	PlatformJP1  Platform = "jp1"
+	PlatformKR   Platform = "kr"
+	PlatformLA1  Platform = "la1"
+	PlatformLA2  Platform = "la2"
+	PlatformNA1  Platform = "na1"
+	PlatformOC1  Platform = "oc1"
+	PlatformPH2  Platform = "ph2"
+	PlatformRU   Platform = "ru"
+	PlatformSG2  Platform = "sg2"
+	PlatformTH2  Platform = "th2"
+	PlatformTR1  Platform = "tr1"
+	PlatformTW2  Platform = "tw2"
+	PlatformVN2  Platform = "vn2"
+)
+
+// DefaultPlatform is used when neither the Config nor the context specify one.
+const DefaultPlatform = PlatformNA1
+
+// Region routes requests to regional endpoints, e.g. match-v1.
+type Region string
+
+// Regional clusters supported by the Riot API.
+const (
+	RegionAmericas Region = "americas"
+	RegionAsia     Region = "asia"
+	RegionEurope   Region = "europe"
+	RegionSEA      Region = "sea"
+)
+
+var platformRegions = map[Platform]Region{
+	PlatformBR1:  RegionAmericas,
+	PlatformLA1:  RegionAmericas,
+	PlatformLA2:  RegionAmericas,
+	PlatformNA1:  RegionAmericas,
+	PlatformEUN1: RegionEurope,
+	PlatformEUW1: RegionEurope,
+	PlatformRU:   RegionEurope,
+	PlatformTR1:  RegionEurope,
+	PlatformJP1:  RegionAsia,
+	PlatformKR:   RegionAsia,
+	PlatformOC1:  RegionSEA,
+	PlatformPH2:  RegionSEA,
+	PlatformSG2:  RegionSEA,
+	PlatformTH2:  RegionSEA,
+	PlatformTW2:  RegionSEA,
+	PlatformVN2:  RegionSEA,
+}
+
+// ParsePlatform validates a case insensitive platform, e.g. "EUW1".
+func ParsePlatform(s string) (Platform, error) {
+	p := Platform(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := platformRegions[p]; !ok {
+		return "", fmt.Errorf("unknown platform: %q", s)
+	}
+
+	return p, nil
+}
+
+// Region is the regional cluster that serves a Platform's matches.
+func (p Platform) Region() Region {
+	if r, ok := platformRegions[p]; ok {
+		return r
+	}
+
+	return RegionAmericas
+}
+
+// PlatformFromMatchID parses the platform prefix of a match ID, e.g. "NA1_3206016526".
+func PlatformFromMatchID(matchID string) (Platform, bool) {
+	i := strings.Index(matchID, "_")
+	if i < 1 {
+		return "", false
+	}
+
+	p, err := ParsePlatform(matchID[:i])
+	if err != nil {
+		return "", false
+	}
+
+	return p, true
+}
+
+type platformKey struct{}
+
+// WithPlatform overrides the Client's configured Platform for calls made with ctx.
+func WithPlatform(ctx context.Context, p Platform) context.Context {
+	return context.WithValue(ctx, platformKey{}, p)
+}
+
+// PlatformFromContext returns a Platform set by WithPlatform.
+func PlatformFromContext(ctx context.Context) (Platform, bool) {
+	p, ok := ctx.Value(platformKey{}).(Platform)
+	return p, ok && p != ""
+}
+
+// platform resolves the Platform for a call.
+// Context overrides take precedence over the Client's Config.
+func (c *Client) platform(ctx context.Context) Platform {
+	if p, ok := PlatformFromContext(ctx); ok {
+		return p
+	}
+
+	if c.Config.Platform != "" {
+		return c.Config.Platform
+	}
+
+	return DefaultPlatform
+}
+
+// endpoint of the Riot API.
+type endpoint struct {
+	// route is the Platform or Region serving the endpoint.
+	route string
+	// method identifies the endpoint for method rate limits.
+	method string
+	path   string
+	query  url.Values
+}
+
+// platformEndpoint for platform scoped methods.
+func (c *Client) platformEndpoint(ctx context.Context, method, format string, a ...interface{}) endpoint {
+	return endpoint{
+		route:  string(c.platform(ctx)),
+		method: method,
+		path:   fmt.Sprintf(format, a...),
+	}
+}
+
+// regionEndpoint for regional methods.
+func (c *Client) regionEndpoint(ctx context.Context, method, format string, a ...interface{}) endpoint {
+	return endpoint{
+		route:  string(c.platform(ctx).Region()),
+		method: method,
+		path:   fmt.Sprintf(format, a...),
+	}
+}
+
+// url of the endpoint. Config.BaseURL replaces the routing host.
+func (c *Client) url(e endpoint) string {
+	base := c.Config.BaseURL
+	if base == "" {
+		base = fmt.Sprintf("https://%s.api.riotgames.com", e.route)
+	}
+
+	u := strings.TrimSuffix(base, "/") + e.path
+	if len(e.query) > 0 {
+		u += "?" + e.query.Encode()
+	}
+
+	return u
+}
@@ -5,63 +5,170 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"path"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/alee792/teamfit/internal/fanout"
 )
 
 type Client struct {
 	client *http.Client
 	Config Config
+	// Limiter may be shared by Clients using the same API key.
+	Limiter *Limiter
 }
 
 type Config struct {
 	APIKey string
+	// Platform of the Client's summoners, e.g. na1 or euw1.
+	// Regional endpoints are derived from the Platform.
+	// Override per call with WithPlatform.
+	Platform Platform
+	// BaseURL replaces https://{route}.api.riotgames.com, e.g. for a local fake.
+	BaseURL string
+	// AppRateLimit seeds the Limiter until the API reports its limits.
+	// Defaults to DefaultAppRateLimit.
+	AppRateLimit string
+	// MaxRetries of a rate limited request. Defaults to DefaultMaxRetries.
+	// A negative value disables retries.
+	MaxRetries int
+	// Parallelism of batch calls like GetSummoners. Defaults to DefaultParallelism.
+	Parallelism int
 }
 
+const (
+	// DefaultMaxRetries of a rate limited request.
+	DefaultMaxRetries = 3
+	// DefaultParallelism of batch calls.
+	DefaultParallelism = 4
+)
+
 func NewClient(client *http.Client, cfg Config) *Client {
+	if cfg.AppRateLimit == "" {
+		cfg.AppRateLimit = DefaultAppRateLimit
+	}
+
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.Parallelism < 1 {
+		cfg.Parallelism = DefaultParallelism
+	}
+
+	limiter, err := NewLimiter(cfg.AppRateLimit)
+	if err != nil {
+		// Fall back to the API's reported limits.
+		limiter, _ = NewLimiter("")
+	}
+
 	return &Client{
-		client: client,
-		Config: cfg,
+		client:  client,
+		Config:  cfg,
+		Limiter: limiter,
+	}
+}
+
+// get an endpoint and decode its JSON response into v.
+// Rate limited requests are retried once the Limiter allows.
+func (c *Client) get(ctx context.Context, e endpoint, v interface{}) error {
+	for attempt := 0; ; attempt++ {
+		if err := c.Limiter.Wait(ctx, e.route, e.method); err != nil {
+			return err
+		}
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(e), nil)
+		if err != nil {
+			return err
+		}
+
+		r.Header.Set("X-Riot-Token", c.Config.APIKey)
+
+		resp, err := c.client.Do(r)
+		if err != nil {
+			return err
+		}
+
+		c.Limiter.Update(e.route, e.method, resp)
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.Config.MaxRetries {
+			resp.Body.Close()
+			continue
+		}
+
+		body := resp.Body
+		defer resp.Body.Close()
+
+		if resp.StatusCode > 299 || resp.StatusCode < 200 {
+			return newAPIError(e.method, resp)
+		}
+
+		return json.NewDecoder(body).Decode(v)
 	}
 }
 
 func (c *Client) GetSummoner(ctx context.Context, name string) (*Summoner, error) {
-	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://na1.api.riotgames.com/tft/summoner/v1/summoners/by-name/", nil)
-	if err != nil {
+	e := c.platformEndpoint(ctx, "summoner-v1.getByName", "/tft/summoner/v1/summoners/by-name/%s", url.PathEscape(name))
+
+	var s Summoner
+	if err := c.get(ctx, e, &s); err != nil {
 		return nil, err
 	}
 
-	r.Header.Set("X-Riot-Token", c.Config.APIKey)
-	r.URL.Path = path.Join(r.URL.Path, name)
+	return &s, nil
+}
+
+// GetSummonerByPUUID, which unlike a name never changes.
+func (c *Client) GetSummonerByPUUID(ctx context.Context, puuid string) (*Summoner, error) {
+	e := c.platformEndpoint(ctx, "summoner-v1.getByPUUID", "/tft/summoner/v1/summoners/by-puuid/%s", url.PathEscape(puuid))
 
-	resp, err := c.client.Do(r)
-	if err != nil {
+	var s Summoner
+	if err := c.get(ctx, e, &s); err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, fmt.Errorf("non 200 status code: %d", resp.StatusCode)
-	}
+	return &s, nil
+}
 
-	body := resp.Body
-	defer resp.Body.Close()
+// GetSummonerByID, the encrypted summoner ID used by league-v1.
+func (c *Client) GetSummonerByID(ctx context.Context, summonerID string) (*Summoner, error) {
+	e := c.platformEndpoint(ctx, "summoner-v1.getBySummonerId", "/tft/summoner/v1/summoners/%s", url.PathEscape(summonerID))
 
 	var s Summoner
-	if err := json.NewDecoder(body).Decode(&s); err != nil {
+	if err := c.get(ctx, e, &s); err != nil {
 		return nil, err
 	}
 
 	return &s, nil
 }
 
+// GetSummoners concurrently, in the order of names.
+// Summoners that could not be retrieved are reported by a *BatchError.
 func (c *Client) GetSummoners(ctx context.Context, names []string) ([]Summoner, error) {
-	var summoners []Summoner
-	for _, name := range names {
-		out, err := c.GetSummoner(ctx, name)
+	out := make([]*Summoner, len(names))
+	errs := fanout.Do(ctx, len(names), c.Config.Parallelism, func(ctx context.Context, i int) error {
+		var err error
+		out[i], err = c.GetSummoner(ctx, names[i])
+		return err
+	})
+
+	var (
+		summoners []Summoner
+		failures  = make(map[string]error)
+	)
+
+	for i, err := range errs {
 		if err != nil {
-			return nil, err
+			failures[names[i]] = err
+			continue
 		}
 
-		summoners = append(summoners, *out)
+		summoners = append(summoners, *out[i])
+	}
+
+	if len(failures) > 0 {
+		return summoners, &BatchError{Errors: failures}
 	}
 
 	return summoners, nil
@@ -69,6 +176,13 @@ func (c *Client) GetSummoners(ctx context.Context, names []string) ([]Summoner,
 
 type ListMatchesRequest struct {
 	PUUID string
+	// Start is the offset into the player's history, newest first.
+	Start int
+	// Count of match IDs to return. Riot defaults to 20.
+	Count int
+	// StartTime and EndTime bound the matches' start times.
+	StartTime time.Time
+	EndTime   time.Time
 }
 
 type ListMatchesResponse struct {
@@ -80,39 +194,39 @@ func (c *Client) ListMatches(ctx context.Context, in *ListMatchesRequest) (*List
 		return nil, fmt.Errorf("invalid PUUID")
 	}
 
-	path := fmt.Sprintf("https://americas.api.riotgames.com/tft/match/v1/matches/by-puuid/%s/ids", in.PUUID)
+	e := c.regionEndpoint(ctx, "match-v1.getMatchIdsByPUUID", "/tft/match/v1/matches/by-puuid/%s/ids", in.PUUID)
+	e.query = in.query()
 
-	r, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
-	if err != nil {
+	var matches []string
+	if err := c.get(ctx, e, &matches); err != nil {
 		return nil, err
 	}
 
-	r.Header.Set("X-Riot-Token", c.Config.APIKey)
+	return &ListMatchesResponse{
+		MatchIDs: matches,
+	}, nil
+}
 
-	resp, err := c.client.Do(r)
-	if err != nil {
-		return nil, err
+func (in *ListMatchesRequest) query() url.Values {
+	q := make(url.Values)
+	if in.Start > 0 {
+		q.Set("start", strconv.Itoa(in.Start))
 	}
 
-	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	if in.Count > 0 {
+		q.Set("count", strconv.Itoa(in.Count))
 	}
 
-	body := resp.Body
-	defer resp.Body.Close()
-
-	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, fmt.Errorf("non 200 HTTP status code: %d", resp.StatusCode)
+	// Riot expects epoch seconds.
+	if !in.StartTime.IsZero() {
+		q.Set("startTime", strconv.FormatInt(in.StartTime.Unix(), 10))
 	}
 
-	var matches []string
-	if err := json.NewDecoder(body).Decode(&matches); err != nil {
-		return nil, err
+	if !in.EndTime.IsZero() {
+		q.Set("endTime", strconv.FormatInt(in.EndTime.Unix(), 10))
 	}
 
-	return &ListMatchesResponse{
-		MatchIDs: matches,
-	}, nil
+	return q
 }
 
 type GetMatchRequest struct {
@@ -128,29 +242,16 @@ func (c *Client) GetMatch(ctx context.Context, in *GetMatchRequest) (*GetMatchRe
 		return nil, fmt.Errorf("invalid matchID")
 	}
 
-	path := fmt.Sprintf("https://americas.api.riotgames.com/tft/match/v1/matches/%s", in.MatchID)
-
-	r, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
+	// Match IDs are prefixed with the platform they were played on,
+	// which always takes precedence over the call's Platform.
+	if p, ok := PlatformFromMatchID(in.MatchID); ok {
+		ctx = WithPlatform(ctx, p)
 	}
 
-	r.Header.Set("X-Riot-Token", c.Config.APIKey)
-
-	resp, err := c.client.Do(r)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
-	}
-
-	body := resp.Body
-	defer resp.Body.Close()
+	e := c.regionEndpoint(ctx, "match-v1.getMatch", "/tft/match/v1/matches/%s", in.MatchID)
 
 	var m Match
-	if err := json.NewDecoder(body).Decode(&m); err != nil {
+	if err := c.get(ctx, e, &m); err != nil {
 		return nil, err
 	}
 
@@ -167,11 +268,16 @@ func (c *Client) GetMostRecentMatch(ctx context.Context, name string) (*Match, e
 
 	mmOut, err := c.ListMatches(ctx, &ListMatchesRequest{
 		PUUID: smnr.PUUID,
+		Count: 1,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if len(mmOut.MatchIDs) < 1 {
+		return nil, fmt.Errorf("no matches found for %s", name)
+	}
+
 	mOut, err := c.GetMatch(ctx, &GetMatchRequest{
 		MatchID: mmOut.MatchIDs[0],
 	})
@@ -182,38 +288,16 @@ func (c *Client) GetMostRecentMatch(ctx context.Context, name string) (*Match, e
 	return &mOut.Match, nil
 }
 
-func (c *Client) GetLeagueEntry(ctx context.Context, summonerID string) (*LeagueEntry, error) {
-	path := fmt.Sprintf("https://na1.api.riotgames.com/tft/league/v1/entries/by-summoner/%s", summonerID)
-
-	r, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	r.Header.Set("X-Riot-Token", c.Config.APIKey)
-
-	resp, err := c.client.Do(r)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
-	}
-
-	body := resp.Body
-	defer resp.Body.Close()
+// GetLeagueEntries for every queue a summoner is ranked in.
+func (c *Client) GetLeagueEntries(ctx context.Context, summonerID string) ([]LeagueEntry, error) {
+	e := c.platformEndpoint(ctx, "league-v1.getLeagueEntriesForSummoner", "/tft/league/v1/entries/by-summoner/%s", summonerID)
 
 	var out []LeagueEntry
-	if err := json.NewDecoder(body).Decode(&out); err != nil {
+	if err := c.get(ctx, e, &out); err != nil {
 		return nil, err
 	}
 
-	if len(out) < 1 {
-		return nil, nil
-	}
-
-	return &out[0], nil
+	return out, nil
 }
 
 // Summoner uses reflects the convention of the Riot API and
@@ -245,18 +329,31 @@ func (s *Summoner) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// Match and its nested types decode Riot's match-v1 schema.
+// Fields the schema adds before this package models them are kept
+// in each type's Extra, as are optional fields sent as zero values,
+// e.g. "win": false, so re-encoding a Match loses nothing.
 type Match struct {
 	Info     Info     `json:"info"`
 	Metadata Metadata `json:"metadata"`
+	Extra    Extra    `json:"-"`
 }
 
 type Info struct {
-	GameTimestamp int           `json:"game_datetime"`
-	Participants  []Participant `json:"participants"`
-	Set           int           `json:"tft_set_number"`
-	GameLength    float32       `json:"game_length"`
-	QueueID       int           `json:"queue_id"`
-	GameVersion   string        `json:"game_version"`
+	GameID          int64         `json:"gameId,omitempty"`
+	GameCreation    int64         `json:"gameCreation,omitempty"`
+	GameTimestamp   int           `json:"game_datetime"`
+	Participants    []Participant `json:"participants"`
+	Set             int           `json:"tft_set_number"`
+	SetCoreName     string        `json:"tft_set_core_name,omitempty"`
+	GameType        string        `json:"tft_game_type,omitempty"`
+	GameVariation   string        `json:"game_variation,omitempty"`
+	GameLength      float32       `json:"game_length"`
+	QueueID         int           `json:"queue_id"`
+	GameVersion     string        `json:"game_version"`
+	MapID           int           `json:"mapId,omitempty"`
+	EndOfGameResult string        `json:"endOfGameResult,omitempty"`
+	Extra           Extra         `json:"-"`
 }
 
 type Participant struct {
@@ -270,12 +367,23 @@ type Participant struct {
 	PUUID                string    `json:"puuid"`
 	TotalDamageToPlayers int       `json:"total_damage_to_players"`
 	Units                []Unit    `json:"units"`
+	GoldLeft             int       `json:"gold_left"`
+	Augments             []string  `json:"augments,omitempty"`
+	// PartnerGroupID pairs Double Up teammates.
+	PartnerGroupID int            `json:"partner_group_id,omitempty"`
+	Missions       map[string]int `json:"missions,omitempty"`
+	Win            bool           `json:"win,omitempty"`
+	RiotIDGameName string         `json:"riotIdGameName,omitempty"`
+	RiotIDTagline  string         `json:"riotIdTagline,omitempty"`
+	Extra          Extra          `json:"-"`
 }
 
 type Companion struct {
-	SkinID    int    `json:"skin_id"`
-	ContentID string `json:"content_id"`
+	SkinID    int    `json:"skin_ID"`
+	ContentID string `json:"content_ID"`
+	ItemID    int    `json:"item_ID,omitempty"`
 	Species   string `json:"species"`
+	Extra     Extra  `json:"-"`
 }
 
 type Trait struct {
@@ -283,20 +391,119 @@ type Trait struct {
 	Name        string `json:"name"`
 	TierCurrent int    `json:"tier_current"`
 	NumUnits    int    `json:"num_units"`
+	// Style is the trait's badge: 0 inactive, then bronze, silver, gold and chromatic.
+	Style int `json:"style,omitempty"`
+	// DisplayName and Icon are set by package staticdata, not the Riot API.
+	DisplayName string `json:"display_name,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Extra       Extra  `json:"-"`
 }
 
 type Unit struct {
-	Tier        int    `json:"tier"`
-	Items       []int  `json:"items"`
-	CharacterID string `json:"character_id"`
-	Name        string `json:"name"`
-	Rarity      int    `json:"rarity"`
+	Tier  int   `json:"tier"`
+	Items []int `json:"items"`
+	// ItemNames replaced numeric Items in later sets.
+	ItemNames   []string `json:"itemNames,omitempty"`
+	CharacterID string   `json:"character_id"`
+	// Chosen is the trait a Chosen unit was offered with.
+	Chosen string `json:"chosen,omitempty"`
+	Name   string `json:"name"`
+	Rarity int    `json:"rarity"`
+	// DisplayName, Cost, Icon and ItemDisplayNames are set
+	// by package staticdata, not the Riot API.
+	DisplayName      string   `json:"display_name,omitempty"`
+	Cost             int      `json:"cost,omitempty"`
+	Icon             string   `json:"icon,omitempty"`
+	ItemDisplayNames []string `json:"item_display_names,omitempty"`
+	Extra            Extra    `json:"-"`
 }
 
 type Metadata struct {
 	DataVersion  string   `json:"data_version"`
 	Participants []string `json:"participants"`
 	MatchID      string   `json:"match_id"`
+	Extra        Extra    `json:"-"`
+}
+
+func (m *Match) UnmarshalJSON(data []byte) error {
+	type match Match
+	return unmarshalExtra(data, (*match)(m), &m.Extra)
+}
+
+func (m Match) MarshalJSON() ([]byte, error) {
+	type match Match
+	return marshalExtra(match(m), m.Extra)
+}
+
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type info Info
+	return unmarshalExtra(data, (*info)(i), &i.Extra)
+}
+
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return marshalExtra(info(i), i.Extra)
+}
+
+func (p *Participant) UnmarshalJSON(data []byte) error {
+	type participant Participant
+	return unmarshalExtra(data, (*participant)(p), &p.Extra)
+}
+
+func (p Participant) MarshalJSON() ([]byte, error) {
+	type participant Participant
+	return marshalExtra(participant(p), p.Extra)
+}
+
+func (c *Companion) UnmarshalJSON(data []byte) error {
+	type companion Companion
+	return unmarshalExtra(data, (*companion)(c), &c.Extra)
+}
+
+func (c Companion) MarshalJSON() ([]byte, error) {
+	type companion Companion
+	return marshalExtra(companion(c), c.Extra)
+}
+
+func (t *Trait) UnmarshalJSON(data []byte) error {
+	type trait Trait
+	return unmarshalExtra(data, (*trait)(t), &t.Extra)
+}
+
+func (t Trait) MarshalJSON() ([]byte, error) {
+	type trait Trait
+	return marshalExtra(trait(t), t.Extra)
+}
+
+func (u *Unit) UnmarshalJSON(data []byte) error {
+	type unit Unit
+	return unmarshalExtra(data, (*unit)(u), &u.Extra)
+}
+
+func (u Unit) MarshalJSON() ([]byte, error) {
+	type unit Unit
+	return marshalExtra(unit(u), u.Extra)
+}
+
+// ItemCount of items the Unit holds. Later sets send ItemNames,
+// sometimes alongside Items for the same items, so ItemNames are
+// preferred when present.
+func (u Unit) ItemCount() int {
+	if len(u.ItemNames) > 0 {
+		return len(u.ItemNames)
+	}
+
+	return len(u.Items)
+}
+
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	type metadata Metadata
+	return unmarshalExtra(data, (*metadata)(m), &m.Extra)
+}
+
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	type metadata Metadata
+	return marshalExtra(metadata(m), m.Extra)
 }
 
 type LeagueEntry struct {
@@ -0,0 +1,44 @@
+package tft_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/alee792/teamfit/pkg/tft/tfttest"
+)
+
+func TestGetSummonersBatchError(t *testing.T) {
+	f, err := tfttest.LoadFixtures("tfttest/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := tfttest.NewServer(f)
+	defer srv.Close()
+
+	names := []string{"Tactician One", "Nobody", "Tactician Two", "Nobody Else"}
+	out, err := srv.APIClient().GetSummoners(context.Background(), names)
+
+	var batch *tft.BatchError
+	if !errors.As(err, &batch) {
+		t.Fatalf("err = %v, want a *tft.BatchError", err)
+	}
+
+	// Failures are keyed by the names that failed.
+	if len(batch.Errors) != 2 {
+		t.Errorf("failures = %v, want Nobody and Nobody Else", batch.Errors)
+	}
+
+	for _, name := range []string{"Nobody", "Nobody Else"} {
+		if err := batch.Errors[name]; !errors.Is(err, tft.ErrNotFound) {
+			t.Errorf("failures[%s] = %v, want not found", name, err)
+		}
+	}
+
+	// The others are returned in order.
+	if len(out) != 2 || out[0].Name != "Tactician One" || out[1].Name != "Tactician Two" {
+		t.Errorf("got %+v, want Tactician One and Two", out)
+	}
+}
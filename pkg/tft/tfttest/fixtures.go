@@ -0,0 +1,172 @@
+package tfttest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/pkg/errors"
+)
+
+// Fixtures are the data served by a Server.
+type Fixtures struct {
+	Accounts  []tft.Account
+	Summoners []tft.Summoner
+	Entries   map[string][]tft.LeagueEntry // Key = Summoner.ID
+	Leagues   []tft.LeagueList
+	// RatedLadders of rated queues like Hyper Roll.
+	RatedLadders map[string][]tft.TopRatedLadderEntry // Key = queue type
+	Matches      []tft.Match
+}
+
+// LoadFixtures from a directory laid out as:
+//
+//	accounts/*.json       a tft.Account per file
+//	summoners/*.json      a tft.Summoner per file
+//	entries/{id}.json     a Summoner's []tft.LeagueEntry, named by Summoner.ID
+//	leagues/*.json        a tft.LeagueList per file
+//	ladders/{queue}.json  a rated queue's []tft.TopRatedLadderEntry
+//	matches/*.json        a tft.Match per file
+//
+// Missing subdirectories are treated as empty.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	f := &Fixtures{
+		Entries:      make(map[string][]tft.LeagueEntry),
+		RatedLadders: make(map[string][]tft.TopRatedLadderEntry),
+	}
+
+	err := eachJSON(filepath.Join(dir, "accounts"), func(name string, bb []byte) error {
+		var a tft.Account
+		if err := json.Unmarshal(bb, &a); err != nil {
+			return err
+		}
+
+		f.Accounts = append(f.Accounts, a)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachJSON(filepath.Join(dir, "summoners"), func(name string, bb []byte) error {
+		var s tft.Summoner
+		if err := json.Unmarshal(bb, &s); err != nil {
+			return err
+		}
+
+		f.Summoners = append(f.Summoners, s)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachJSON(filepath.Join(dir, "entries"), func(name string, bb []byte) error {
+		var ee []tft.LeagueEntry
+		if err := json.Unmarshal(bb, &ee); err != nil {
+			return err
+		}
+
+		f.Entries[strings.TrimSuffix(name, ".json")] = ee
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachJSON(filepath.Join(dir, "leagues"), func(name string, bb []byte) error {
+		var l tft.LeagueList
+		if err := json.Unmarshal(bb, &l); err != nil {
+			return err
+		}
+
+		f.Leagues = append(f.Leagues, l)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachJSON(filepath.Join(dir, "ladders"), func(name string, bb []byte) error {
+		var ll []tft.TopRatedLadderEntry
+		if err := json.Unmarshal(bb, &ll); err != nil {
+			return err
+		}
+
+		f.RatedLadders[strings.TrimSuffix(name, ".json")] = ll
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachJSON(filepath.Join(dir, "matches"), func(name string, bb []byte) error {
+		var m tft.Match
+		if err := json.Unmarshal(bb, &m); err != nil {
+			return err
+		}
+
+		f.Matches = append(f.Matches, m)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *Fixtures) summonerByName(name string) (tft.Summoner, bool) {
+	for _, s := range f.Summoners {
+		if normalizeName(s.Name) == normalizeName(name) {
+			return s, true
+		}
+	}
+
+	return tft.Summoner{}, false
+}
+
+// normalizeName like Riot, which ignores case and whitespace.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), ""))
+}
+
+// eachJSON calls fn with the contents of every JSON file in dir.
+func eachJSON(dir string, fn func(name string, bb []byte) error) error {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if info.IsDir() || filepath.Ext(info.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, info.Name())
+
+		bb, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(info.Name(), bb); err != nil {
+			return errors.Wrapf(err, "invalid fixture %s", path)
+		}
+	}
+
+	return nil
+}
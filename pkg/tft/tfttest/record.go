@@ -0,0 +1,127 @@
+package tfttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recording of a response, saved as JSON.
+type recording struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that saves every response under Dir
+// so it can be replayed by a Replayer. Request headers, including the
+// API key, are never saved.
+type Recorder struct {
+	Dir string
+	// Transport makes the real requests. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+func (rec *Recorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	t := rec.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+
+	resp, err := t.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bb, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(bb))
+
+	// Riot responds with JSON, but keep anything else as a JSON string.
+	body := json.RawMessage(bb)
+	if !json.Valid(bb) {
+		body, _ = json.Marshal(string(bb))
+	}
+
+	path := recordingPath(rec.Dir, r)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	out, err := json.MarshalIndent(recording{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(path, out, 0644); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that serves responses saved by a Recorder.
+// Requests that were never recorded fail.
+type Replayer struct {
+	Dir string
+}
+
+func (rep *Replayer) RoundTrip(r *http.Request) (*http.Response, error) {
+	bb, err := ioutil.ReadFile(recordingPath(rep.Dir, r))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no recording for %s %s", r.Method, r.URL)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rec recording
+	if err := json.Unmarshal(bb, &rec); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       r,
+	}, nil
+}
+
+// recordingPath mirrors a request's host, path and query under dir, e.g.
+// dir/americas.api.riotgames.com/tft/match/v1/matches/NA1_1.json
+func recordingPath(dir string, r *http.Request) string {
+	var segments []string
+	for _, seg := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		// Keep escaped segments, e.g. names with spaces, on a single path element.
+		segments = append(segments, url.PathEscape(seg))
+	}
+
+	name := filepath.Join(append([]string{dir, r.URL.Host}, segments...)...)
+	if r.URL.RawQuery != "" {
+		// Encode sorts the query so equivalent requests share a recording.
+		name += "@" + url.PathEscape(r.URL.Query().Encode())
+	}
+
+	return name + ".json"
+}
@@ -0,0 +1,90 @@
+package tfttest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/alee792/teamfit/pkg/tft"
+)
+
+const fixtureMatch = "NA1_3206016526"
+
+// calls made against both a recording and a replaying Client.
+func calls(ctx context.Context, c *tft.Client) (*tft.Summoner, *tft.ListMatchesResponse, *tft.GetMatchResponse, error) {
+	smnr, err := c.GetSummoner(ctx, "Tactician One")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	list, err := c.ListMatches(ctx, &tft.ListMatchesRequest{PUUID: smnr.PUUID, Count: 5})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	m, err := c.GetMatch(ctx, &tft.GetMatchRequest{MatchID: fixtureMatch})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return smnr, list, m, nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	f, err := LoadFixtures("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "tfttest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+
+	srv := NewServer(f)
+	recorder := tft.NewClient(&http.Client{
+		Transport: &Recorder{Dir: dir, Transport: srv.Client().Transport},
+	}, srv.Config())
+
+	wantSmnr, wantList, wantMatch, err := calls(ctx, recorder)
+	srv.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wantList.MatchIDs) != 1 || wantList.MatchIDs[0] != fixtureMatch {
+		t.Fatalf("recorded match IDs = %v, want [%s]", wantList.MatchIDs, fixtureMatch)
+	}
+
+	// The Server is closed, so every response must come from the recording.
+	replayer := tft.NewClient(&http.Client{
+		Transport: &Replayer{Dir: dir},
+	}, srv.Config())
+
+	smnr, list, m, err := calls(ctx, replayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(smnr, wantSmnr) {
+		t.Errorf("replayed summoner = %+v, want %+v", smnr, wantSmnr)
+	}
+
+	if !reflect.DeepEqual(list, wantList) {
+		t.Errorf("replayed match IDs = %+v, want %+v", list, wantList)
+	}
+
+	if !reflect.DeepEqual(m, wantMatch) {
+		t.Errorf("replayed match differs from recording")
+	}
+
+	if _, err := replayer.GetSummoner(ctx, "Tactician Two"); err == nil {
+		t.Errorf("replaying an unrecorded request succeeded")
+	}
+}
@@ -0,0 +1,391 @@
+// Package tfttest provides a fake Riot API and record/replay transports
+// so TFT clients can be exercised without the network or an API key.
+//
+// The testdata directory holds a small fixture set for LoadFixtures.
+package tfttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alee792/teamfit/pkg/tft"
+	"github.com/go-chi/chi"
+)
+
+// APIKey accepted by a Server.
+const APIKey = "tfttest"
+
+// Server is an httptest.Server that fakes the Riot API from Fixtures.
+// It enforces and reports an app rate limit like the real API.
+type Server struct {
+	*httptest.Server
+	Fixtures *Fixtures
+	// AppRateLimit enforced by the Server, e.g. "20:1,100:120".
+	// An empty limit is unlimited.
+	AppRateLimit string
+
+	mux      sync.Mutex
+	windows  []*window
+	requests int
+}
+
+type window struct {
+	max    int
+	period time.Duration
+	count  int
+	reset  time.Time
+}
+
+// NewServer starts a fake Riot API. Close it when finished.
+func NewServer(f *Fixtures) *Server {
+	s := &Server{
+		Fixtures:     f,
+		AppRateLimit: tft.DefaultAppRateLimit,
+	}
+
+	r := chi.NewRouter()
+	r.Use(s.authenticate, s.rateLimit)
+	r.Get("/tft/summoner/v1/summoners/by-name/{name}", s.getSummonerByName)
+	r.Get("/tft/summoner/v1/summoners/by-puuid/{puuid}", s.getSummonerByPUUID)
+	r.Get("/tft/summoner/v1/summoners/{id}", s.getSummonerByID)
+	r.Get("/riot/account/v1/accounts/by-riot-id/{gameName}/{tagLine}", s.getAccountByRiotID)
+	r.Get("/riot/account/v1/accounts/by-puuid/{puuid}", s.getAccountByPUUID)
+	r.Get("/tft/league/v1/entries/by-summoner/{id}", s.getLeagueEntries)
+	r.Get("/tft/league/v1/entries/{tier}/{division}", s.listLeagueEntries)
+	r.Get("/tft/league/v1/challenger", s.getApexLeague(tft.TierChallenger))
+	r.Get("/tft/league/v1/grandmaster", s.getApexLeague(tft.TierGrandmaster))
+	r.Get("/tft/league/v1/master", s.getApexLeague(tft.TierMaster))
+	r.Get("/tft/league/v1/leagues/{id}", s.getLeague)
+	r.Get("/tft/league/v1/rated-ladders/{queue}/top", s.getRatedLadder)
+	r.Get("/tft/match/v1/matches/by-puuid/{puuid}/ids", s.listMatches)
+	r.Get("/tft/match/v1/matches/{id}", s.getMatch)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, http.StatusNotFound, "Data not found - no route")
+	})
+
+	s.Server = httptest.NewServer(r)
+
+	return s
+}
+
+// Config for a tft.Client that targets the Server.
+func (s *Server) Config() tft.Config {
+	return tft.Config{
+		APIKey:  APIKey,
+		BaseURL: s.URL,
+	}
+}
+
+// APIClient targeting the Server.
+func (s *Server) APIClient() *tft.Client {
+	return tft.NewClient(s.Client(), s.Config())
+}
+
+// Requests served, including rejected requests.
+func (s *Server) Requests() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.requests
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Riot-Token") {
+		case "":
+			writeStatus(w, http.StatusUnauthorized, "Unauthorized")
+		case APIKey:
+			next.ServeHTTP(w, r)
+		default:
+			writeStatus(w, http.StatusForbidden, "Forbidden")
+		}
+	})
+}
+
+// rateLimit counts requests in fixed windows and sets Riot's rate limit headers.
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mux.Lock()
+		s.requests++
+
+		if s.windows == nil {
+			s.windows = parseLimit(s.AppRateLimit)
+		}
+
+		var (
+			now        = time.Now()
+			limits     []string
+			counts     []string
+			retryAfter time.Duration
+		)
+
+		for _, win := range s.windows {
+			if !now.Before(win.reset) {
+				win.count = 0
+				win.reset = now.Add(win.period)
+			}
+
+			win.count++
+			if win.count > win.max && win.reset.Sub(now) > retryAfter {
+				retryAfter = win.reset.Sub(now)
+			}
+
+			secs := strconv.Itoa(int(win.period / time.Second))
+			limits = append(limits, strconv.Itoa(win.max)+":"+secs)
+			counts = append(counts, strconv.Itoa(win.count)+":"+secs)
+		}
+		s.mux.Unlock()
+
+		if len(limits) > 0 {
+			w.Header().Set("X-App-Rate-Limit", strings.Join(limits, ","))
+			w.Header().Set("X-App-Rate-Limit-Count", strings.Join(counts, ","))
+		}
+
+		if retryAfter > 0 {
+			secs := int((retryAfter + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			w.Header().Set("X-Rate-Limit-Type", "application")
+			writeStatus(w, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) getSummonerByName(w http.ResponseWriter, r *http.Request) {
+	smnr, ok := s.Fixtures.summonerByName(chi.URLParam(r, "name"))
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "Data not found - summoner not found")
+		return
+	}
+
+	writeJSON(w, smnr)
+}
+
+func (s *Server) getSummonerByPUUID(w http.ResponseWriter, r *http.Request) {
+	puuid := chi.URLParam(r, "puuid")
+	for _, smnr := range s.Fixtures.Summoners {
+		if smnr.PUUID == puuid {
+			writeJSON(w, smnr)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusNotFound, "Data not found - summoner not found")
+}
+
+func (s *Server) getSummonerByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, smnr := range s.Fixtures.Summoners {
+		if smnr.ID == id {
+			writeJSON(w, smnr)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusNotFound, "Data not found - summoner not found")
+}
+
+func (s *Server) getAccountByRiotID(w http.ResponseWriter, r *http.Request) {
+	var (
+		gameName = chi.URLParam(r, "gameName")
+		tagLine  = chi.URLParam(r, "tagLine")
+	)
+
+	// Riot IDs are case insensitive.
+	for _, acct := range s.Fixtures.Accounts {
+		if strings.EqualFold(acct.GameName, gameName) && strings.EqualFold(acct.TagLine, tagLine) {
+			writeJSON(w, acct)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusNotFound, "Data not found - No results found for player with riot id "+gameName+"#"+tagLine)
+}
+
+func (s *Server) getAccountByPUUID(w http.ResponseWriter, r *http.Request) {
+	puuid := chi.URLParam(r, "puuid")
+	for _, acct := range s.Fixtures.Accounts {
+		if acct.PUUID == puuid {
+			writeJSON(w, acct)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusNotFound, "Data not found - account not found")
+}
+
+func (s *Server) getLeagueEntries(w http.ResponseWriter, r *http.Request) {
+	entries := s.Fixtures.Entries[chi.URLParam(r, "id")]
+	if entries == nil {
+		entries = []tft.LeagueEntry{}
+	}
+
+	writeJSON(w, entries)
+}
+
+// leagueEntriesPageSize matches the Riot API's page size.
+const leagueEntriesPageSize = 205
+
+func (s *Server) listLeagueEntries(w http.ResponseWriter, r *http.Request) {
+	var (
+		tier     = chi.URLParam(r, "tier")
+		division = chi.URLParam(r, "division")
+		page     = atoi(r.URL.Query().Get("page"), 1)
+	)
+
+	var all []tft.LeagueEntry
+	for _, ee := range s.Fixtures.Entries {
+		for _, e := range ee {
+			if e.Tier == tier && e.Rank == division {
+				all = append(all, e)
+			}
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].SummonerID < all[j].SummonerID
+	})
+
+	out := []tft.LeagueEntry{}
+	for i := (page - 1) * leagueEntriesPageSize; i >= 0 && i < len(all) && len(out) < leagueEntriesPageSize; i++ {
+		out = append(out, all[i])
+	}
+
+	writeJSON(w, out)
+}
+
+func (s *Server) getApexLeague(tier string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, l := range s.Fixtures.Leagues {
+			if l.Tier == tier {
+				writeJSON(w, l)
+				return
+			}
+		}
+
+		writeStatus(w, http.StatusNotFound, "Data not found - league not found")
+	}
+}
+
+func (s *Server) getLeague(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, l := range s.Fixtures.Leagues {
+		if l.LeagueID == id {
+			writeJSON(w, l)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusNotFound, "Data not found - league not found")
+}
+
+func (s *Server) getRatedLadder(w http.ResponseWriter, r *http.Request) {
+	ladder := s.Fixtures.RatedLadders[chi.URLParam(r, "queue")]
+	if ladder == nil {
+		ladder = []tft.TopRatedLadderEntry{}
+	}
+
+	writeJSON(w, ladder)
+}
+
+func (s *Server) listMatches(w http.ResponseWriter, r *http.Request) {
+	var (
+		puuid = chi.URLParam(r, "puuid")
+		q     = r.URL.Query()
+		start = atoi(q.Get("start"), 0)
+		count = atoi(q.Get("count"), 20)
+		from  = atoi(q.Get("startTime"), 0)
+		to    = atoi(q.Get("endTime"), 0)
+	)
+
+	// Riot lists matches newest first.
+	var mm []tft.Match
+	for _, m := range s.Fixtures.Matches {
+		secs := m.Info.GameTimestamp / 1000
+		if (from > 0 && secs < from) || (to > 0 && secs > to) {
+			continue
+		}
+
+		for _, p := range m.Metadata.Participants {
+			if p == puuid {
+				mm = append(mm, m)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(mm, func(i, j int) bool {
+		return mm[i].Info.GameTimestamp > mm[j].Info.GameTimestamp
+	})
+
+	ids := []string{}
+	for i := start; i < len(mm) && len(ids) < count; i++ {
+		ids = append(ids, mm[i].Metadata.MatchID)
+	}
+
+	writeJSON(w, ids)
+}
+
+func (s *Server) getMatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, m := range s.Fixtures.Matches {
+		if m.Metadata.MatchID == id {
+			writeJSON(w, m)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusNotFound, "Data not found - match file not found")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeStatus writes an error formatted like the Riot API's.
+func writeStatus(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]tft.Status{
+		"status": {Message: msg, StatusCode: code},
+	})
+}
+
+func atoi(s string, fallback int) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+
+	return i
+}
+
+// parseLimit parses comma separated "requests:seconds" pairs, skipping invalid pairs.
+func parseLimit(s string) []*window {
+	var ww []*window
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+		if len(parts) != 2 {
+			continue
+		}
+
+		max, err1 := strconv.Atoi(parts[0])
+		secs, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		ww = append(ww, &window{max: max, period: time.Duration(secs) * time.Second})
+	}
+
+	return ww
+}
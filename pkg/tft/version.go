@@ -0,0 +1,50 @@
+package tft
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var patchRE = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// ParsePatch extracts a major.minor patch, e.g. "9.22", from a game version
+// like "Version 9.22.292.3135 (Nov 04 2019/17:07:03) [PUBLIC] ".
+func ParsePatch(gameVersion string) string {
+	m := patchRE.FindStringSubmatch(gameVersion)
+	if m == nil {
+		return ""
+	}
+
+	return m[1] + "." + m[2]
+}
+
+// Patch the match was played on.
+func (i *Info) Patch() string {
+	return ParsePatch(i.GameVersion)
+}
+
+// ComparePatches like strings.Compare, but numerically, so "9.22" < "10.1".
+// Unparseable parts compare as zero.
+func ComparePatches(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}